@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/cache"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// activityEventsPerKind is how many commits, releases and merged pull requests are fetched per repository
+	activityEventsPerKind = 30
+	// activityConcurrency caps the repositories fetched at once for GET /activity
+	activityConcurrency = 4
+)
+
+func GetProjectActivity(c *gin.Context) {
+
+	projectID := c.Param("projectID")
+
+	var project structs.Projects
+	if err := initializers.DB.Where("id = ? AND is_enabled = ?", projectID, true).Preload("ProjectURLs").First(&project).Error; err != nil {
+		c.JSON(400, gin.H{"error": "Project does not exist"})
+		return
+	}
+
+	owner, name, err := github.ParseRepositoryURL(project.ProjectURLs.GitHubURL)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Project has no linked GitHub repository"})
+		return
+	}
+
+	types, ok := parseActivityTypes(c)
+	if !ok {
+		return
+	}
+
+	events, result, err := getRepositoryActivity(owner, name)
+	if err != nil {
+		if errors.Is(err, github.ErrNotFound) {
+			c.JSON(404, gin.H{"error": "Repository not found"})
+			return
+		}
+		respondGitHubError(c, err, "Failed to fetch project activity")
+		return
+	}
+
+	for i := range events {
+		events[i].ProjectId = project.ID
+		events[i].ProjectName = project.ProjectName
+	}
+
+	setCacheHeaders(c, result)
+	respondActivityPage(c, filterActivity(events, types))
+}
+
+// GetActivity merges the activity of every enabled project's repository into one timeline
+func GetActivity(c *gin.Context) {
+
+	types, ok := parseActivityTypes(c)
+	if !ok {
+		return
+	}
+
+	var projects []structs.Projects
+	if err := initializers.DB.Where("is_enabled = ?", true).Preload("ProjectURLs").Order("id ASC").Find(&projects).Error; err != nil {
+		log.Error("Error retrieving projects: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving projects"})
+		return
+	}
+
+	// Projects sharing a repository share its events, credited to the first project
+	type repository struct {
+		owner, name string
+		project     structs.Projects
+	}
+	var repositories []repository
+	seen := make(map[string]bool)
+	for _, project := range projects {
+		owner, name, err := github.ParseRepositoryURL(project.ProjectURLs.GitHubURL)
+		key := strings.ToLower(owner + "/" + name)
+		if err != nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+		repositories = append(repositories, repository{owner: owner, name: name, project: project})
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		events  = []structs.ActivityEventModel{}
+		lastErr error
+		failed  int
+	)
+	slots := make(chan struct{}, activityConcurrency)
+
+	for _, repo := range repositories {
+		wg.Add(1)
+		go func(repo repository) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			repoEvents, _, err := getRepositoryActivity(repo.owner, repo.name)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			// One broken link shouldn't take the whole timeline down
+			if err != nil {
+				log.Warn("Error fetching activity for ", repo.owner, "/", repo.name, ": ", err)
+				lastErr = err
+				failed++
+				return
+			}
+
+			for _, event := range repoEvents {
+				event.ProjectId = repo.project.ID
+				event.ProjectName = repo.project.ProjectName
+				events = append(events, event)
+			}
+		}(repo)
+	}
+	wg.Wait()
+
+	if len(repositories) > 0 && failed == len(repositories) {
+		respondGitHubError(c, lastErr, "Failed to fetch activity")
+		return
+	}
+
+	utils.SortActivity(events)
+	respondActivityPage(c, filterActivity(events, types))
+}
+
+// getRepositoryActivity returns the repository's recent events from the cache, newest first
+func getRepositoryActivity(owner string, name string) ([]structs.ActivityEventModel, *cache.Result, error) {
+	result, err := fetchGitHubCached(utils.RepositoryActivityCacheKey(owner, name), func(ctx context.Context) (interface{}, error) {
+		activity, err := initializers.GitHub.GetRepositoryActivity(ctx, owner, name, activityEventsPerKind)
+		if err != nil {
+			return nil, err
+		}
+
+		// The token may see private repositories, their activity isn't shown on either timeline
+		if activity.IsPrivate {
+			return []structs.ActivityEventModel{}, nil
+		}
+
+		return utils.NormaliseRepositoryActivity(owner+"/"+name, activity), nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []structs.ActivityEventModel
+	if err := json.Unmarshal(result.Value, &events); err != nil {
+		return nil, nil, err
+	}
+
+	return events, result, nil
+}
+
+// parseActivityTypes reads the optional comma separated ?type= filter, e.g. ?type=RELEASE,PULL_REQUEST
+func parseActivityTypes(c *gin.Context) (map[structs.ActivityType]bool, bool) {
+	typeParam := c.Query("type")
+	if typeParam == "" {
+		return nil, true
+	}
+
+	types := make(map[structs.ActivityType]bool)
+	for _, value := range strings.Split(typeParam, ",") {
+		activityType := structs.ActivityType(strings.ToUpper(strings.TrimSpace(value)))
+		switch activityType {
+		case structs.COMMIT_ACTIVITY, structs.RELEASE_ACTIVITY, structs.PULL_REQUEST_ACTIVITY:
+			types[activityType] = true
+		default:
+			c.JSON(400, gin.H{"error": "Invalid activity type " + value})
+			return nil, false
+		}
+	}
+
+	return types, true
+}
+
+func filterActivity(events []structs.ActivityEventModel, types map[structs.ActivityType]bool) []structs.ActivityEventModel {
+	if types == nil {
+		return events
+	}
+
+	filtered := []structs.ActivityEventModel{}
+	for _, event := range events {
+		if types[event.Type] {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// respondActivityPage pages through the cached events, which only go back activityEventsPerKind of each kind per
+// repository. "limit" reports that cap and "hasMore" whether a repository has older events past it, so total
+// isn't read as the whole history.
+func respondActivityPage(c *gin.Context, events []structs.ActivityEventModel) {
+	page, pageSize := getPagination(c)
+
+	perKind := make(map[string]int)
+	hasMore := false
+	for _, event := range events {
+		key := event.Repository + "\n" + string(event.Type)
+		perKind[key]++
+		if perKind[key] >= activityEventsPerKind {
+			hasMore = true
+		}
+	}
+
+	start := (page - 1) * pageSize
+	if start > len(events) {
+		start = len(events)
+	}
+	end := start + pageSize
+	if end > len(events) {
+		end = len(events)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":     events[start:end],
+		"pagination": structs.PaginationModel{Page: page, PageSize: pageSize, Total: int64(len(events))},
+		"limit":      activityEventsPerKind,
+		"hasMore":    hasMore,
+	})
+}
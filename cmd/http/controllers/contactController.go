@@ -1,11 +1,18 @@
 package controllers
 
 import (
+	"encoding/json"
 	"os"
-	"regexp"
+	"strings"
+	"time"
 
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/mailer"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/spam"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 )
 
 func ContactEmail(c *gin.Context) {
@@ -13,7 +20,12 @@ func ContactEmail(c *gin.Context) {
 		Name      string `json:"name" binding:"required"`
 		Email     string `json:"email" binding:"required"`
 		Message   string `json:"message" binding:"required"`
-		Recaptcha string `json:"recaptcha" binding:"required"`
+		Captcha   string `json:"captcha"`
+		Recaptcha string `json:"recaptcha"` // Deprecated: sent by older frontends, use captcha
+		FormToken string `json:"formToken"`
+		Website   string `json:"website"` // Honeypot, hidden from humans
+		// Fields holds the extra fields configured in the contact form schema
+		Fields map[string]json.RawMessage `json:"fields"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -21,41 +33,383 @@ func ContactEmail(c *gin.Context) {
 		return
 	}
 
-	// Verify recaptcha
-	if !utils.VerifyRecaptcha(request.Recaptcha) {
-		c.JSON(400, gin.H{"error": "Recaptcha verification failed"})
+	captchaToken := request.Captcha
+	if captchaToken == "" {
+		captchaToken = request.Recaptcha
+	}
+
+	// Verify captcha
+	captchaResult, err := initializers.Captcha.Verify(captchaToken, c.ClientIP())
+	if err != nil {
+		log.Warn("Contact form captcha rejected: ", err)
+		c.JSON(400, gin.H{"error": "Captcha verification failed"})
 		return
 	}
 
-	// Verify that the provided email is valid (regex)
-	if !verifyEmail(request.Email) {
+	if err := utils.ValidateEmailAddress(request.Email); err != nil {
 		c.JSON(400, gin.H{"error": "Invalid email address"})
 		return
 	}
 
-	// Send email (tell the user that the email was sent)
-	utils.SendEmail(
-		request.Email,
-		"Portfolio Contact Form - Email Sent",
-		"Hello "+request.Name+",<br><br>"+
-			"Thank you for reaching out to me. I will get back to you as soon as possible.<br><br>"+
-			"<strong>Your Message:</strong><br>"+request.Message+"<br><br>"+
-			"Best Regards,<br>Jack",
-	)
+	schema, err := utils.GetContactFormSchema()
+	if err != nil {
+		log.Error("Error retrieving contact form schema: ", err)
+		c.JSON(500, gin.H{"error": "Error storing message"})
+		return
+	}
 
-	// Send email (send the email to me)
-	utils.SendEmail(
-		os.Getenv("EMAIL_CONTACT"),
-		"Portfolio Contact Form - New Message",
-		"Name: "+request.Name+"<br>"+
-			"Email: "+request.Email+"<br>"+
-			"Message: "+request.Message,
-	)
+	fields, err := schema.ValidateFields(request.Fields)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	verdict := initializers.SpamFilter.Check(spam.Submission{
+		Name:      request.Name,
+		Email:     request.Email,
+		Message:   request.Message,
+		Honeypot:  request.Website,
+		FormToken: request.FormToken,
+	})
+
+	// Store the message first, so it is never lost if sending the emails fails
+	contactMessage := structs.ContactMessages{
+		Name:         request.Name,
+		Email:        request.Email,
+		Message:      request.Message,
+		IPAddress:    c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+		CaptchaScore: captchaResult.Score,
+		Fields:       fields,
+		IsSpam:       verdict.IsSpam,
+		SpamScore:    verdict.Score,
+		SpamReasons:  verdict.Reasons(),
+		MessageHash:  spam.MessageHash(request.Message),
+	}
+
+	if err := initializers.DB.Create(&contactMessage).Error; err != nil {
+		log.Error("Error storing contact message: ", err)
+		c.JSON(500, gin.H{"error": "Error storing message"})
+		return
+	}
+
+	// Quarantine spam without emailing anyone, the response is the same so spammers can't tell
+	if verdict.IsSpam {
+		log.Infof("Contact message %d quarantined as spam (score %.2f): %s", contactMessage.ID, verdict.Score, strings.Join(verdict.Reasons(), "; "))
+		c.JSON(200, gin.H{"message": "Email sent"})
+		return
+	}
+
+	queueContactAutoReply(contactMessage)
+
+	queueContactNotification(contactMessage)
 
 	c.JSON(200, gin.H{"message": "Email sent"})
 }
 
-func verifyEmail(email string) bool {
-	emailRegex := regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
-	return emailRegex.MatchString(email)
+// Returns the extra fields the contact form should render
+func GetContactFormSchema(c *gin.Context) {
+
+	schema, err := utils.GetContactFormSchema()
+	if err != nil {
+		log.Error("Error retrieving contact form schema: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving contact form"})
+		return
+	}
+
+	c.JSON(200, gin.H{"fields": schema.Fields})
+}
+
+func GetContactFormToken(c *gin.Context) {
+	c.JSON(200, gin.H{"token": spam.IssueFormToken(initializers.FormTokenSecret, time.Now())})
+}
+
+func GetContactMessages(c *gin.Context) {
+
+	page, pageSize := getPagination(c)
+
+	query := initializers.DB.Model(&structs.ContactMessages{})
+
+	switch c.DefaultQuery("status", "inbox") {
+	case "inbox":
+		query = query.Where("is_spam = ? AND is_archived = ?", false, false)
+	case "unread":
+		query = query.Where("is_spam = ? AND is_archived = ? AND is_read = ?", false, false, false)
+	case "starred":
+		query = query.Where("is_spam = ? AND is_starred = ?", false, true)
+	case "archived":
+		query = query.Where("is_spam = ? AND is_archived = ?", false, true)
+	case "spam":
+		query = query.Where("is_spam = ?", true)
+	case "all":
+	default:
+		c.JSON(400, gin.H{"error": "Invalid status, expected inbox, unread, starred, archived, spam or all"})
+		return
+	}
+
+	if search := c.Query("search"); search != "" {
+		like := "%" + search + "%"
+		query = query.Where("name LIKE ? OR email LIKE ? OR message LIKE ?", like, like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error("Error counting contact messages: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving messages"})
+		return
+	}
+
+	var messages []structs.ContactMessages
+	if err := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&messages).Error; err != nil {
+		log.Error("Error retrieving contact messages: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving messages"})
+		return
+	}
+
+	c.JSON(200, gin.H{"messages": messages, "pagination": structs.PaginationModel{Page: page, PageSize: pageSize, Total: total}})
+}
+
+func GetContactMessage(c *gin.Context) {
+
+	messageID := c.Param("messageID")
+
+	var message structs.ContactMessages
+	if err := initializers.DB.Preload("Replies").First(&message, "id = ?", messageID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No message found with this ID"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": message})
+}
+
+func MarkContactMessageRead(c *gin.Context) {
+	var request struct {
+		Read *bool `json:"read" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	updateContactMessageFlag(c, "is_read", *request.Read)
+}
+
+func ArchiveContactMessage(c *gin.Context) {
+	var request struct {
+		Archived *bool `json:"archived" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	updateContactMessageFlag(c, "is_archived", *request.Archived)
+}
+
+func StarContactMessage(c *gin.Context) {
+	var request struct {
+		Starred *bool `json:"starred" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	updateContactMessageFlag(c, "is_starred", *request.Starred)
+}
+
+// MarkContactMessageSpam moves a message in or out of quarantine, releasing a message sends the
+// notification email that was held back
+func MarkContactMessageSpam(c *gin.Context) {
+
+	messageID := c.Param("messageID")
+
+	var request struct {
+		Spam *bool `json:"spam" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	var message structs.ContactMessages
+	if err := initializers.DB.First(&message, "id = ?", messageID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No message found with this ID"})
+		return
+	}
+
+	// Only the request that actually clears the flag releases the message, so concurrent releases don't email twice
+	result := initializers.DB.Model(&message).Where("is_spam = ?", !*request.Spam).Update("is_spam", *request.Spam)
+	if result.Error != nil {
+		log.Error("Error updating contact message: ", result.Error)
+		c.JSON(500, gin.H{"error": "Error updating message"})
+		return
+	}
+	message.IsSpam = *request.Spam
+
+	// Released false positives get the receipt and notification the quarantine withheld
+	if !*request.Spam && result.RowsAffected == 1 {
+		queueContactAutoReply(message)
+		queueContactNotification(message)
+	}
+
+	c.JSON(200, gin.H{"message": "Message updated successfully", "data": message})
+}
+
+func DeleteContactMessage(c *gin.Context) {
+
+	messageID := c.Param("messageID")
+
+	var message structs.ContactMessages
+	if err := initializers.DB.First(&message, "id = ?", messageID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No message found with this ID"})
+		return
+	}
+
+	if err := initializers.DB.Delete(&message).Error; err != nil {
+		log.Error("Error deleting contact message: ", err)
+		c.JSON(500, gin.H{"error": "Error deleting message"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Message deleted successfully"})
+}
+
+func ReplyToContactMessage(c *gin.Context) {
+
+	messageID := c.Param("messageID")
+
+	var request struct {
+		Subject string `json:"subject"`
+		Body    string `json:"body" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	var message structs.ContactMessages
+	if err := initializers.DB.First(&message, "id = ?", messageID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No message found with this ID"})
+		return
+	}
+
+	// Render the reply, quoting the original message
+	content, err := utils.RenderEmail(utils.CONTACT_REPLY_EMAIL, utils.ContactReplyEmailData{
+		Name:    message.Name,
+		Message: message.Message,
+		SentAt:  message.CreatedAt,
+		Body:    request.Body,
+	})
+	if err != nil {
+		log.Error("Error rendering contact reply: ", err)
+		c.JSON(500, gin.H{"error": "Error sending reply"})
+		return
+	}
+
+	if request.Subject != "" {
+		content.Subject = request.Subject
+	}
+
+	email, err := utils.QueueEmail(mailer.Message{
+		To:      message.Email,
+		ReplyTo: os.Getenv("EMAIL_CONTACT"),
+		Content: *content,
+	})
+	if err != nil {
+		log.Error("Error queueing contact reply: ", err)
+		c.JSON(500, gin.H{"error": "Error sending reply"})
+		return
+	}
+
+	reply := structs.ContactReplies{
+		MessageId: message.ID,
+		UserId:    c.GetUint("userId"),
+		EmailId:   email.ID,
+		Subject:   content.Subject,
+		Body:      request.Body,
+	}
+
+	if err := initializers.DB.Create(&reply).Error; err != nil {
+		log.Error("Error storing contact reply: ", err)
+		c.JSON(500, gin.H{"error": "Reply queued but could not be stored"})
+		return
+	}
+
+	initializers.DB.Model(&message).Update("is_read", true)
+
+	c.JSON(200, gin.H{"message": "Reply queued successfully", "reply": reply})
+}
+
+func updateContactMessageFlag(c *gin.Context, column string, value bool) {
+
+	messageID := c.Param("messageID")
+
+	var message structs.ContactMessages
+	if err := initializers.DB.First(&message, "id = ?", messageID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No message found with this ID"})
+		return
+	}
+
+	if err := initializers.DB.Model(&message).Update(column, value).Error; err != nil {
+		log.Error("Error updating contact message: ", err)
+		c.JSON(500, gin.H{"error": "Error updating message"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Message updated successfully", "data": message})
+}
+
+func contactEmailData(message structs.ContactMessages) utils.ContactEmailData {
+	schema, err := utils.GetContactFormSchema()
+	if err != nil {
+		log.Error("Error retrieving contact form schema: ", err)
+	}
+
+	return utils.ContactEmailData{
+		Name:    message.Name,
+		Email:   message.Email,
+		Message: message.Message,
+		Fields:  schema.Labelled(message.Fields),
+	}
+}
+
+// Queue email (tell the user that the email was sent), unless the auto-reply is disabled
+func queueContactAutoReply(message structs.ContactMessages) {
+	settings, err := utils.GetAutoReplySettings()
+	if err != nil {
+		log.Error("Error retrieving auto-reply settings: ", err)
+		return
+	}
+
+	if !settings.Enabled {
+		return
+	}
+
+	emailData := contactEmailData(message)
+	if err := settings.Apply(&emailData); err != nil {
+		log.Error("Error rendering auto-reply: ", err)
+		return
+	}
+
+	if _, err := utils.QueueTemplatedEmail(mailer.Message{To: message.Email}, utils.CONTACT_RECEIPT_EMAIL, emailData); err != nil {
+		log.Error("Error queueing contact receipt email: ", err)
+	}
+}
+
+// Queue email (send the email to me, replying goes straight to the visitor) & chat notifications
+func queueContactNotification(message structs.ContactMessages) {
+	notification := mailer.Message{To: os.Getenv("EMAIL_CONTACT"), ReplyTo: message.Email}
+	if _, err := utils.QueueTemplatedEmail(notification, utils.CONTACT_NOTIFICATION_EMAIL, contactEmailData(message)); err != nil {
+		log.Error("Error queueing contact notification email: ", err)
+	}
+
+	if err := utils.NotifyContactMessage(message); err != nil {
+		log.Error("Error queueing contact notifications: ", err)
+	}
 }
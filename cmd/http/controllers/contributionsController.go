@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/contributions"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetMergedContributions sums the default GitHub user's contributions with the configured GitLab and Gitea
+// accounts, in the same calendar shape as GetCommitHistory
+func GetMergedContributions(c *gin.Context) {
+
+	year, ok := parseContributionYear(c)
+	if !ok {
+		return
+	}
+
+	settings, err := utils.GetGitHubUserSettings()
+	if err != nil {
+		log.Error("Error retrieving GitHub user settings: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch contributions"})
+		return
+	}
+
+	accounts := initializers.ContributionAccounts
+	if settings.DefaultUser != "" {
+		accounts = append([]contributions.Account{{Provider: contributions.NewGitHubProvider(initializers.GitHub), Login: settings.DefaultUser}}, accounts...)
+	}
+
+	if len(accounts) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No contribution providers are configured"})
+		return
+	}
+
+	// An account failing fails the fetch, so the last complete calendar is served stale instead
+	result, err := fetchGitHubCached(utils.MergedContributionsCacheKey(settings.DefaultUser, year), func(ctx context.Context) (interface{}, error) {
+		days, results, err := contributions.Merge(ctx, accounts, year)
+		if err != nil {
+			return nil, err
+		}
+
+		// Other providers only report the range asked for, GitHub knows every year the user contributed in
+		years := contributions.Years(days)
+		if settings.DefaultUser != "" {
+			if recent, err := getContributionsCollection(settings.DefaultUser, 0); err == nil {
+				years = contributions.MergeYears(years, recent.ContributionYears)
+			}
+		}
+
+		return gin.H{
+			"user": gin.H{"contributionsCollection": gin.H{
+				"contributionYears":    years,
+				"contributionCalendar": contributions.BuildCalendar(days, year, time.Now()),
+			}},
+			"providers": results,
+		}, nil
+	})
+	if err != nil {
+		if errors.Is(err, contributions.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		respondGitHubError(c, err, "Failed to fetch contributions")
+		return
+	}
+
+	setCacheHeaders(c, result)
+	c.JSON(http.StatusOK, gin.H{"data": json.RawMessage(result.Value)})
+}
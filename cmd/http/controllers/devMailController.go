@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/mailer"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// These routes are only registered when EMAIL_DRIVER is dev
+
+func GetCapturedMail(c *gin.Context) {
+
+	devMailer := initializers.Mailer.(*mailer.DevMailer)
+
+	messages, err := devMailer.List()
+	if err != nil {
+		log.Error("Error listing captured mail: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving captured mail", "fullError": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"messages": messages})
+}
+
+func GetCapturedMailMessage(c *gin.Context) {
+
+	devMailer := initializers.Mailer.(*mailer.DevMailer)
+
+	raw, err := devMailer.Raw(c.Param("messageID"))
+	if err == mailer.ErrMessageNotFound {
+		c.JSON(404, gin.H{"error": "No captured message found with this ID"})
+		return
+	} else if err != nil {
+		log.Error("Error reading captured mail: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving captured mail", "fullError": err.Error()})
+		return
+	}
+
+	c.Data(200, "message/rfc822", raw)
+}
+
+func ClearCapturedMail(c *gin.Context) {
+
+	devMailer := initializers.Mailer.(*mailer.DevMailer)
+
+	if err := devMailer.Clear(); err != nil {
+		log.Error("Error clearing captured mail: ", err)
+		c.JSON(500, gin.H{"error": "Error clearing captured mail", "fullError": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Captured mail cleared successfully"})
+}
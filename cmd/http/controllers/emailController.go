@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetOutboundEmails(c *gin.Context) {
+
+	page, pageSize := getPagination(c)
+
+	query := initializers.DB.Model(&structs.OutboundEmails{})
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error("Error counting outbound emails: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving emails"})
+		return
+	}
+
+	var emails []structs.OutboundEmails
+	if err := query.Omit("html", "text").Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&emails).Error; err != nil {
+		log.Error("Error retrieving outbound emails: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving emails"})
+		return
+	}
+
+	c.JSON(200, gin.H{"emails": emails, "pagination": structs.PaginationModel{Page: page, PageSize: pageSize, Total: total}})
+}
+
+func GetOutboundEmail(c *gin.Context) {
+
+	emailID := c.Param("emailID")
+
+	var email structs.OutboundEmails
+	if err := initializers.DB.Preload("EmailAttempts").First(&email, "id = ?", emailID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No email found with this ID"})
+		return
+	}
+
+	c.JSON(200, gin.H{"email": email})
+}
+
+func RequeueOutboundEmail(c *gin.Context) {
+
+	emailID := c.Param("emailID")
+
+	var email structs.OutboundEmails
+	if err := initializers.DB.First(&email, "id = ?", emailID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No email found with this ID"})
+		return
+	}
+
+	if email.Status != structs.EMAIL_DEAD {
+		c.JSON(400, gin.H{"error": "Only dead-lettered emails can be re-queued"})
+		return
+	}
+
+	if err := utils.RequeueEmail(&email); err != nil {
+		log.Error("Error re-queueing email: ", err)
+		c.JSON(500, gin.H{"error": "Error re-queueing email"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Email re-queued successfully", "email": email})
+}
+
+func GetEmailTemplates(c *gin.Context) {
+	c.JSON(200, gin.H{"templates": utils.EmailTemplateNames()})
+}
+
+// Renders an email template with sample data (?format=html or text returns the raw part)
+func PreviewEmailTemplate(c *gin.Context) {
+
+	templateName := c.Param("templateName")
+
+	content, err := utils.PreviewEmail(templateName)
+	if err == utils.ErrUnknownEmailTemplate {
+		c.JSON(404, gin.H{"error": "No email template found with this name"})
+		return
+	} else if err != nil {
+		log.Error("Error rendering email template: ", err)
+		c.JSON(500, gin.H{"error": "Error rendering email template", "fullError": err.Error()})
+		return
+	}
+
+	switch c.Query("format") {
+	case "html":
+		c.Data(200, "text/html; charset=utf-8", []byte(content.HTML))
+	case "text":
+		c.Data(200, "text/plain; charset=utf-8", []byte(content.Text))
+	default:
+		c.JSON(200, gin.H{"email": content})
+	}
+}
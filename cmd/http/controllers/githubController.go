@@ -1,124 +1,315 @@
 package controllers
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"io"
+	"errors"
+	"math"
 	"net/http"
-	"os"
+	"strconv"
+	"time"
 
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/cache"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 )
 
-// GraphQL request payload
-type GraphQLRequest struct {
-	Query     string                 `json:"query"`
-	Variables map[string]interface{} `json:"variables"`
-}
+const githubFetchTimeout = 30 * time.Second
+
+func GetCommitHistory(c *gin.Context) {
+
+	userName, ok := resolveGitHubUser(c)
+	if !ok {
+		return
+	}
+
+	year, ok := parseContributionYear(c)
+	if !ok {
+		return
+	}
 
-// Expected structure of the GraphQL response
-type GraphQLResponse struct {
-	Data   map[string]interface{} `json:"data"`
-	Errors []interface{}          `json:"errors"`
+	result, err := getContributions(userName, year)
+	if err != nil {
+		respondGitHubError(c, err, "Failed to fetch commit history")
+		return
+	}
+
+	setCacheHeaders(c, result)
+	c.JSON(http.StatusOK, gin.H{"data": json.RawMessage(result.Value)})
 }
 
-func GetCommitHistory(c *gin.Context) {
-	// GraphQL query
-	query := `
-		query ($login: String!) {
-			user(login: $login) {
-				contributionsCollection {
-					contributionYears
-					contributionCalendar {
-						totalContributions
-						weeks {
-							contributionDays {
-								weekday
-								date
-								contributionCount
-								color
-							}
-						}
-					}
-				}
-			}
-		}
-	`
-	
-	userName := c.Query("user")
-	if userName == "" {
-		log.Error("User parameter is required")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User parameter is required"})
+// GetCommitHistorySVG renders the contribution calendar as an SVG for READMEs and OpenGraph images
+func GetCommitHistorySVG(c *gin.Context) {
+
+	userName, ok := resolveGitHubUser(c)
+	if !ok {
 		return
 	}
 
-	// Create the request payload
-	requestPayload := GraphQLRequest{
-		Query: query,
-		Variables: map[string]interface{}{
-			"login": userName,
-		},
+	year, ok := parseContributionYear(c)
+	if !ok {
+		return
 	}
 
-	// Convert payload to JSON
-	payloadBytes, err := json.Marshal(requestPayload)
+	themeName := c.DefaultQuery("theme", "light")
+	theme, ok := utils.HeatmapThemes[themeName]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid theme"})
+		return
+	}
+
+	options := utils.HeatmapOptions{CellSize: utils.DEFAULT_HEATMAP_CELL_SIZE, UseDayColors: themeName == "light"}
+
+	if palette := c.Query("palette"); palette != "" {
+		colors, err := utils.ParseHeatmapPalette(palette)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid palette", "fullError": err.Error()})
+			return
+		}
+		theme.Palette = colors
+		options.UseDayColors = false
+	}
+	options.Theme = theme
+
+	if size := c.Query("size"); size != "" {
+		cellSize, err := strconv.Atoi(size)
+		if err != nil || cellSize < utils.MIN_HEATMAP_CELL_SIZE || cellSize > utils.MAX_HEATMAP_CELL_SIZE {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size, must be between " +
+				strconv.Itoa(utils.MIN_HEATMAP_CELL_SIZE) + " and " + strconv.Itoa(utils.MAX_HEATMAP_CELL_SIZE)})
+			return
+		}
+		options.CellSize = cellSize
+	}
+
+	result, err := getContributions(userName, year)
 	if err != nil {
-		log.Error("Error marshaling request payload: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request payload"})
+		respondGitHubError(c, err, "Failed to fetch commit history")
 		return
 	}
 
-	// Make HTTP POST request to the GitHub GraphQL API
-	req, err := http.NewRequest("POST", "https://api.github.com/graphql", bytes.NewBuffer(payloadBytes))
+	collection, err := decodeContributions(result)
 	if err != nil {
-		log.Error("Error creating HTTP request: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request"})
+		log.Error("Error decoding cached contributions: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch commit history"})
 		return
 	}
 
-	// Set the authorization header using GitHub token
-	githubToken := os.Getenv("GITHUB_ACCESS_TOKEN")
-	if githubToken == "" {
-		log.Error("GitHub access token is not set")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "GitHub access token is not set"})
+	calendar := collection.ContributionCalendar
+	options.Caption = utils.FormatThousands(calendar.TotalContributions) + " contributions in the last year"
+	if year != 0 {
+		options.Caption = utils.FormatThousands(calendar.TotalContributions) + " contributions in " + strconv.Itoa(year)
+	}
+
+	svg := utils.RenderHeatmap(calendar, options)
+
+	hash := sha256.Sum256(svg)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+
+	setCacheHeaders(c, result)
+	// Let CDNs and image proxies keep serving the image for a day while they revalidate, and a week if we are down
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(math.Ceil(result.MaxAge.Seconds())))+
+		", stale-while-revalidate=86400, stale-if-error=604800")
+	c.Header("ETag", etag)
+
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
 		return
 	}
-	req.Header.Set("Authorization", "Bearer "+githubToken)
-	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Error("Error making HTTP request: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to make request"})
+	c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", svg)
+}
+
+func GetContributionStats(c *gin.Context) {
+
+	userName, ok := resolveGitHubUser(c)
+	if !ok {
 		return
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	result, err := initializers.GitHubCache.Fetch(utils.GitHubCacheKey(utils.CONTRIBUTION_STATS_CACHE, userName), func() ([]byte, error) {
+		// The last 12 months, which also lists every year the user has contributed in
+		recent, err := getContributionsCollection(userName, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		days := recent.ContributionCalendar.Days()
+		for _, year := range recent.ContributionYears {
+			collection, err := getContributionsCollection(userName, year)
+			if err != nil {
+				return nil, err
+			}
+			days = append(days, collection.ContributionCalendar.Days()...)
+		}
+
+		return json.Marshal(github.ComputeStats(days, time.Now()))
+	})
 	if err != nil {
-		log.Error("Error reading response body: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response"})
+		respondGitHubError(c, err, "Failed to fetch contribution stats")
 		return
 	}
 
-	// Parse JSON response
-	var graphqlResponse GraphQLResponse
-	err = json.Unmarshal(body, &graphqlResponse)
+	setCacheHeaders(c, result)
+	c.JSON(http.StatusOK, gin.H{"data": json.RawMessage(result.Value)})
+}
+
+func GetPinnedRepositories(c *gin.Context) {
+
+	userName, ok := resolveGitHubUser(c)
+	if !ok {
+		return
+	}
+
+	result, err := fetchGitHubCached(utils.GitHubCacheKey(utils.PINNED_REPOSITORIES_CACHE, userName), func(ctx context.Context) (interface{}, error) {
+		return initializers.GitHub.GetPinnedRepositories(ctx, userName)
+	})
 	if err != nil {
-		log.Error("Error unmarshaling response: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse response"})
+		respondGitHubError(c, err, "Failed to fetch pinned repositories")
 		return
 	}
 
-	// Handle potential errors returned by the GraphQL API
-	if len(graphqlResponse.Errors) > 0 {
-		log.Error("GraphQL API returned errors: ", graphqlResponse.Errors)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch commit history"})
+	setCacheHeaders(c, result)
+	c.JSON(http.StatusOK, gin.H{"data": json.RawMessage(result.Value)})
+}
+
+func GetLanguageBreakdown(c *gin.Context) {
+
+	userName, ok := resolveGitHubUser(c)
+	if !ok {
+		return
+	}
+
+	result, err := fetchGitHubCached(utils.GitHubCacheKey(utils.LANGUAGES_CACHE, userName), func(ctx context.Context) (interface{}, error) {
+		return initializers.GitHub.GetLanguageBreakdown(ctx, userName)
+	})
+	if err != nil {
+		respondGitHubError(c, err, "Failed to fetch languages")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": graphqlResponse.Data})
-}
\ No newline at end of file
+	setCacheHeaders(c, result)
+	c.JSON(http.StatusOK, gin.H{"data": json.RawMessage(result.Value)})
+}
+
+// getContributions returns the cached contributions response for the user and year (0 for the last 12 months)
+func getContributions(userName string, year int) (*cache.Result, error) {
+	cacheKey := utils.GitHubCacheKey(utils.CONTRIBUTIONS_CACHE, userName, strconv.Itoa(year))
+	return fetchGitHubCached(cacheKey, func(ctx context.Context) (interface{}, error) {
+		collection, err := initializers.GitHub.GetContributions(ctx, userName, year)
+		if err != nil {
+			return nil, err
+		}
+
+		return gin.H{"user": gin.H{"contributionsCollection": collection}}, nil
+	})
+}
+
+// fetchGitHubCached caches the JSON of whatever load returns under cacheKey
+func fetchGitHubCached(cacheKey string, load func(ctx context.Context) (interface{}, error)) (*cache.Result, error) {
+	return initializers.GitHubCache.Fetch(cacheKey, func() ([]byte, error) {
+		// Not the request context, the fetch may finish in the background
+		ctx, cancel := context.WithTimeout(context.Background(), githubFetchTimeout)
+		defer cancel()
+
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(value)
+	})
+}
+
+func getContributionsCollection(userName string, year int) (*github.ContributionsCollection, error) {
+	result, err := getContributions(userName, year)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeContributions(result)
+}
+
+func decodeContributions(result *cache.Result) (*github.ContributionsCollection, error) {
+	var response struct {
+		User struct {
+			ContributionsCollection github.ContributionsCollection `json:"contributionsCollection"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(result.Value, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.User.ContributionsCollection, nil
+}
+
+// parseContributionYear reads the optional ?year=, 0 (the default) means the last 12 months
+func parseContributionYear(c *gin.Context) (int, bool) {
+	yearParam := c.Query("year")
+	if yearParam == "" {
+		return 0, true
+	}
+
+	year, err := strconv.Atoi(yearParam)
+	if err != nil || year < 2008 || year > time.Now().Year() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		return 0, false
+	}
+
+	return year, true
+}
+
+// resolveGitHubUser returns the requested ?user= (or the default user) if it is allowed, otherwise
+// it responds with 404 so the endpoints can't be used to look up arbitrary GitHub users
+func resolveGitHubUser(c *gin.Context) (string, bool) {
+	settings, err := utils.GetGitHubUserSettings()
+	if err != nil {
+		log.Error("Error retrieving GitHub user settings: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch GitHub data"})
+		return "", false
+	}
+
+	userName := c.Query("user")
+	if userName == "" {
+		userName = settings.DefaultUser
+	}
+
+	if userName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User parameter is required"})
+		return "", false
+	}
+
+	if !settings.IsAllowed(userName) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return "", false
+	}
+
+	return userName, true
+}
+
+// respondGitHubError maps GitHub client errors onto responses, hiding upstream details
+func respondGitHubError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, github.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, github.ErrRateLimited):
+		log.Warn("GitHub rate limit exceeded: ", err)
+		retryAfter := github.RetryAfter(err, initializers.GitHub.RateLimit())
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": message})
+	default:
+		log.Error(message, ": ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// setCacheHeaders exposes the cache status (X-Cache: HIT, MISS or STALE) and age of the response
+func setCacheHeaders(c *gin.Context, result *cache.Result) {
+	c.Header("X-Cache", string(result.Status))
+	c.Header("Age", strconv.Itoa(int(result.Age.Seconds())))
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(math.Ceil(result.MaxAge.Seconds()))))
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func GetMedia(c *gin.Context) {
+
+	if err := utils.SyncMediaLibrary(initializers.Storage, c.Query("refresh") == "true"); err != nil {
+		log.Error("Error syncing media library: ", err)
+		c.JSON(500, gin.H{"error": "Error syncing media library"})
+		return
+	}
+
+	page, pageSize := getPagination(c)
+
+	query := initializers.DB.Model(&structs.MediaAssets{})
+
+	// search matches the object key, content type or category, e.g. "image/png" or "projects"
+	if search := c.Query("search"); search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("object_key LIKE ? OR content_type LIKE ? OR category LIKE ?", pattern, pattern, pattern)
+	}
+
+	if category := c.Query("category"); category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error("Error counting media assets: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving media"})
+		return
+	}
+
+	var assets []structs.MediaAssets
+	if err := query.Order("uploaded_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&assets).Error; err != nil {
+		log.Error("Error retrieving media assets: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving media"})
+		return
+	}
+
+	media, err := buildMediaAssetModels(assets)
+	if err != nil {
+		log.Error("Error retrieving media usage: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving media"})
+		return
+	}
+
+	c.JSON(200, gin.H{"media": media, "pagination": structs.PaginationModel{Page: page, PageSize: pageSize, Total: total}})
+}
+
+func GetMediaAsset(c *gin.Context) {
+
+	mediaID := c.Param("mediaID")
+
+	var asset structs.MediaAssets
+	if err := initializers.DB.First(&asset, "id = ?", mediaID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No media found with this ID"})
+		return
+	}
+
+	media, err := buildMediaAssetModels([]structs.MediaAssets{asset})
+	if err != nil {
+		log.Error("Error retrieving media usage: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving media"})
+		return
+	}
+
+	c.JSON(200, gin.H{"media": media[0]})
+}
+
+func DeleteMediaAsset(c *gin.Context) {
+
+	mediaID := c.Param("mediaID")
+
+	var asset structs.MediaAssets
+	if err := initializers.DB.First(&asset, "id = ?", mediaID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No media found with this ID"})
+		return
+	}
+
+	// Block deleting assets that are still referenced
+	usage, err := utils.GetMediaUsage([]string{asset.ObjectKey})
+	if err != nil {
+		log.Error("Error retrieving media usage: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving media usage"})
+		return
+	}
+
+	if assetUsage, inUse := usage[asset.ObjectKey]; inUse {
+		c.JSON(409, gin.H{"error": "Media is still in use", "usage": assetUsage})
+		return
+	}
+
+	// Delete the row first so a failed object delete rolls it back, rather than leaving a row without an object
+	err = initializers.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(&asset).Error; err != nil {
+			return err
+		}
+
+		return initializers.Storage.Delete(asset.ObjectKey)
+	})
+	if err != nil {
+		log.Error("Error deleting media asset: ", err)
+		c.JSON(500, gin.H{"error": "Error deleting media"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Media deleted successfully"})
+}
+
+func GetDuplicateMedia(c *gin.Context) {
+
+	groups, err := utils.FindDuplicateMedia()
+	if err != nil {
+		log.Error("Error finding duplicate media: ", err)
+		c.JSON(500, gin.H{"error": "Error finding duplicate media"})
+		return
+	}
+
+	c.JSON(200, gin.H{"duplicates": groups})
+}
+
+func MergeDuplicateMedia(c *gin.Context) {
+
+	merged, err := utils.MergeDuplicateMedia(initializers.Storage)
+	if err != nil {
+		log.Error("Error merging duplicate media: ", err)
+		c.JSON(500, gin.H{"error": "Error merging duplicate media", "merged": merged})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Duplicate media merged successfully", "merged": merged})
+}
+
+func buildMediaAssetModels(assets []structs.MediaAssets) ([]structs.MediaAssetModel, error) {
+	keys := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		keys = append(keys, asset.ObjectKey)
+	}
+
+	usage, err := utils.GetMediaUsage(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	media := make([]structs.MediaAssetModel, 0, len(assets))
+	for _, asset := range assets {
+		assetUsage, inUse := usage[asset.ObjectKey]
+		if assetUsage.Projects == nil {
+			assetUsage.Projects = []structs.MediaUsageReference{}
+		}
+		if assetUsage.Technologies == nil {
+			assetUsage.Technologies = []structs.MediaUsageReference{}
+		}
+
+		media = append(media, structs.MediaAssetModel{MediaAssets: asset, InUse: inUse, Usage: assetUsage})
+	}
+
+	return media, nil
+}
+
+// reads the page & pageSize query parameters (pageSize is capped at 100)
+func getPagination(c *gin.Context) (int, int) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.Query("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 24
+	} else if pageSize > 100 {
+		pageSize = 100
+	}
+
+	return page, pageSize
+}
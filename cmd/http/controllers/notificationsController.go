@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type notificationChannelRequest struct {
+	Name     string                          `json:"name" binding:"required"`
+	Type     structs.NotificationChannelType `json:"type" binding:"required"`
+	URL      string                          `json:"url"`
+	Topic    string                          `json:"topic"`
+	Secret   *string                         `json:"secret"` // Omit on update to keep the current secret
+	Template string                          `json:"template"`
+	// IsEnabled defaults to true
+	IsEnabled *bool `json:"isEnabled"`
+}
+
+func (request notificationChannelRequest) apply(channel *structs.NotificationChannels) {
+	channel.Name = request.Name
+	channel.Type = request.Type
+	channel.URL = request.URL
+	channel.Topic = request.Topic
+	channel.Template = request.Template
+	channel.IsEnabled = request.IsEnabled == nil || *request.IsEnabled
+
+	if request.Secret != nil {
+		channel.Secret = *request.Secret
+	}
+}
+
+func GetNotificationChannels(c *gin.Context) {
+
+	var channels []structs.NotificationChannels
+	if err := initializers.DB.Order("created_at ASC").Find(&channels).Error; err != nil {
+		log.Error("Error retrieving notification channels: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving notification channels"})
+		return
+	}
+
+	c.JSON(200, gin.H{"channels": channels})
+}
+
+func GetNotificationChannel(c *gin.Context) {
+
+	channelID := c.Param("channelID")
+
+	var channel structs.NotificationChannels
+	if err := initializers.DB.First(&channel, "id = ?", channelID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No notification channel found with this ID"})
+		return
+	}
+
+	c.JSON(200, gin.H{"channel": channel})
+}
+
+func CreateNotificationChannel(c *gin.Context) {
+
+	var request notificationChannelRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	var channel structs.NotificationChannels
+	request.apply(&channel)
+
+	if err := utils.ValidateNotificationChannel(channel); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid notification channel", "fullError": err.Error()})
+		return
+	}
+
+	if err := initializers.DB.Create(&channel).Error; err != nil {
+		log.Error("Error creating notification channel: ", err)
+		c.JSON(500, gin.H{"error": "Error creating notification channel"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Notification channel created successfully", "channel": channel})
+}
+
+func UpdateNotificationChannel(c *gin.Context) {
+
+	channelID := c.Param("channelID")
+
+	var request notificationChannelRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	var channel structs.NotificationChannels
+	if err := initializers.DB.First(&channel, "id = ?", channelID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No notification channel found with this ID"})
+		return
+	}
+
+	request.apply(&channel)
+
+	if err := utils.ValidateNotificationChannel(channel); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid notification channel", "fullError": err.Error()})
+		return
+	}
+
+	if err := initializers.DB.Save(&channel).Error; err != nil {
+		log.Error("Error updating notification channel: ", err)
+		c.JSON(500, gin.H{"error": "Error updating notification channel"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Notification channel updated successfully", "channel": channel})
+}
+
+func DeleteNotificationChannel(c *gin.Context) {
+
+	channelID := c.Param("channelID")
+
+	var channel structs.NotificationChannels
+	if err := initializers.DB.First(&channel, "id = ?", channelID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No notification channel found with this ID"})
+		return
+	}
+
+	if err := initializers.DB.Delete(&channel).Error; err != nil {
+		log.Error("Error deleting notification channel: ", err)
+		c.JSON(500, gin.H{"error": "Error deleting notification channel"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Notification channel deleted successfully"})
+}
+
+// Sends a sample contact notification to the channel straight away, reporting any delivery error
+func TestNotificationChannel(c *gin.Context) {
+
+	channelID := c.Param("channelID")
+
+	var channel structs.NotificationChannels
+	if err := initializers.DB.First(&channel, "id = ?", channelID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No notification channel found with this ID"})
+		return
+	}
+
+	if err := utils.SendTestNotification(channel); err != nil {
+		c.JSON(502, gin.H{"error": "Error sending test notification", "fullError": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Test notification sent successfully"})
+}
+
+func GetNotificationDeliveries(c *gin.Context) {
+
+	page, pageSize := getPagination(c)
+
+	query := initializers.DB.Model(&structs.NotificationDeliveries{})
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if channelID := c.Query("channelId"); channelID != "" {
+		query = query.Where("channel_id = ?", channelID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error("Error counting notification deliveries: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving notification deliveries"})
+		return
+	}
+
+	var deliveries []structs.NotificationDeliveries
+	if err := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&deliveries).Error; err != nil {
+		log.Error("Error retrieving notification deliveries: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving notification deliveries"})
+		return
+	}
+
+	c.JSON(200, gin.H{"deliveries": deliveries, "pagination": structs.PaginationModel{Page: page, PageSize: pageSize, Total: total}})
+}
+
+func RequeueNotificationDelivery(c *gin.Context) {
+
+	deliveryID := c.Param("deliveryID")
+
+	var delivery structs.NotificationDeliveries
+	if err := initializers.DB.First(&delivery, "id = ?", deliveryID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No notification delivery found with this ID"})
+		return
+	}
+
+	if delivery.Status != structs.DELIVERY_DEAD {
+		c.JSON(400, gin.H{"error": "Only dead-lettered notifications can be re-queued"})
+		return
+	}
+
+	if err := utils.RequeueNotification(&delivery); err != nil {
+		log.Error("Error re-queueing notification: ", err)
+		c.JSON(500, gin.H{"error": "Error re-queueing notification"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Notification re-queued successfully", "delivery": delivery})
+}
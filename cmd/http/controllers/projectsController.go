@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"time"
 
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
@@ -106,6 +108,10 @@ func CreateProject(c *gin.Context) {
 		return
 	}
 
+	if projectURLs.GitHubURL != "" {
+		utils.QueueRepositorySync(project.ID)
+	}
+
 	c.JSON(200, gin.H{"project": project})
 }
 
@@ -232,6 +238,9 @@ func UpdateProject(c *gin.Context) {
 		return
 	}
 
+	// Picks up a changed or removed GitHub URL
+	utils.QueueRepositorySync(project.ID)
+
 	c.JSON(200, gin.H{"project": project})
 }
 
@@ -248,6 +257,17 @@ func AssignProjectImages(c *gin.Context) {
 		return
 	}
 
+	// Validate the image references (object keys or storage URLs)
+	imageKeys := make([]string, 0, len(newProjectImages.ImageURLs))
+	for _, imageURL := range newProjectImages.ImageURLs {
+		imageKey, err := utils.ResolveStorageKey(initializers.Storage, imageURL)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Invalid imageURL", "fullError": err.Error()})
+			return
+		}
+		imageKeys = append(imageKeys, imageKey)
+	}
+
 	// Start a transaction
 	tx := initializers.DB.Begin()
 	if tx.Error != nil {
@@ -278,17 +298,10 @@ func AssignProjectImages(c *gin.Context) {
 	}
 
 	// Create the new images
-	for _, imageURL := range newProjectImages.ImageURLs {
-		// Validate technologyImage URL
-		if err := utils.ValidateS3URL(initializers.S3Session, imageURL); err != nil {
-			c.JSON(400, gin.H{"error": "Invalid imageURL", "fullError": err.Error()})
-			tx.Rollback()
-			return
-		}
-
+	for _, imageKey := range imageKeys {
 		projectImage := structs.ProjectImages{
 			ProjectId: project.ID,
-			ImageURL:  imageURL,
+			ImageKey:  imageKey,
 		}
 
 		if err := tx.Create(&projectImage).Error; err != nil {
@@ -299,6 +312,15 @@ func AssignProjectImages(c *gin.Context) {
 		}
 	}
 
+	// Register the images in the media library, duplicate uploads are merged into the existing asset
+	mergedKeys, err := utils.RegisterMediaReferences(tx, initializers.Storage, imageKeys...)
+	if err != nil {
+		log.Error("Error registering project images: ", err)
+		c.JSON(500, gin.H{"error": "Error creating project image"})
+		tx.Rollback()
+		return
+	}
+
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
 		log.Error("Error committing transaction: ", err)
@@ -306,6 +328,8 @@ func AssignProjectImages(c *gin.Context) {
 		return
 	}
 
+	utils.DeleteMediaObjects(initializers.Storage, mergedKeys)
+
 	c.JSON(200, gin.H{"message": "Project images assigned successfully"})
 }
 
@@ -333,10 +357,15 @@ func DeleteProject(c *gin.Context) {
 	c.JSON(200, gin.H{"message": "Project deleted successfully"})
 }
 
+// publicRepository leaves out the snapshot of a private repository, which the access token can see but visitors can't
+func publicRepository(db *gorm.DB) *gorm.DB {
+	return db.Where("is_private = ?", false)
+}
+
 func GetProjects(c *gin.Context) {
 
 	var projects []structs.Projects
-	result := initializers.DB.Preload("ProjectImages").Preload("ProjectTechnologies").Preload("ProjectURLs").Find(&projects)
+	result := initializers.DB.Preload("ProjectImages").Preload("ProjectTechnologies").Preload("ProjectURLs").Preload("Repository", publicRepository).Find(&projects)
 
 	if result.Error != nil {
 		c.JSON(500, gin.H{"error": "Error retrieving projects"})
@@ -351,7 +380,7 @@ func GetProject(c *gin.Context) {
 	projectID := c.Param("projectID")
 
 	var project structs.Projects
-	result := initializers.DB.Where("id = ?", projectID).Preload("ProjectImages").Preload("ProjectTechnologies").Preload("ProjectURLs").First(&project)
+	result := initializers.DB.Where("id = ?", projectID).Preload("ProjectImages").Preload("ProjectTechnologies").Preload("ProjectURLs").Preload("Repository", publicRepository).First(&project)
 
 	if result.Error != nil {
 		c.JSON(400, gin.H{"error": "Project does not exist"})
@@ -360,3 +389,64 @@ func GetProject(c *gin.Context) {
 
 	c.JSON(200, gin.H{"project": project})
 }
+
+func ResyncProjectRepository(c *gin.Context) {
+
+	projectID := c.Param("projectID")
+
+	var project structs.Projects
+	if err := initializers.DB.Where("id = ?", projectID).First(&project).Error; err != nil {
+		c.JSON(400, gin.H{"error": "Project does not exist"})
+		return
+	}
+
+	// syncError is what the failed sync recorded on the snapshot, which is otherwise kept out of responses
+	repository, err := utils.SyncProjectRepository(project.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrNoRepository):
+			c.JSON(400, gin.H{"error": "Project has no GitHub URL"})
+		case errors.Is(err, github.ErrInvalidRepositoryURL):
+			c.JSON(400, gin.H{"error": "Project GitHub URL is not a repository URL", "syncError": err.Error()})
+		case errors.Is(err, github.ErrNotFound):
+			c.JSON(404, gin.H{"error": "Repository not found", "syncError": err.Error()})
+		default:
+			log.Error("Error syncing project repository: ", err)
+			c.JSON(502, gin.H{"error": "Error syncing repository", "syncError": err.Error()})
+		}
+		return
+	}
+
+	suggestions, err := utils.SuggestProjectTechnologies(project.ID)
+	if err != nil {
+		log.Error("Error suggesting project technologies: ", err)
+		c.JSON(500, gin.H{"error": "Error suggesting technologies"})
+		return
+	}
+
+	c.JSON(200, gin.H{"repository": repository, "syncError": repository.SyncError, "technologySuggestions": suggestions})
+}
+
+func GetProjectTechnologySuggestions(c *gin.Context) {
+
+	projectID := c.Param("projectID")
+
+	var project structs.Projects
+	if err := initializers.DB.Where("id = ?", projectID).First(&project).Error; err != nil {
+		c.JSON(400, gin.H{"error": "Project does not exist"})
+		return
+	}
+
+	suggestions, err := utils.SuggestProjectTechnologies(project.ID)
+	if err != nil {
+		if errors.Is(err, utils.ErrNoRepository) {
+			c.JSON(400, gin.H{"error": "Project repository has not been synced"})
+			return
+		}
+		log.Error("Error suggesting project technologies: ", err)
+		c.JSON(500, gin.H{"error": "Error suggesting technologies"})
+		return
+	}
+
+	c.JSON(200, gin.H{"technologySuggestions": suggestions})
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetSettings(c *gin.Context) {
+
+	autoReply, err := utils.GetAutoReplySettings()
+	if err != nil {
+		log.Error("Error retrieving auto-reply settings: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving settings"})
+		return
+	}
+
+	contactForm, err := utils.GetContactFormSchema()
+	if err != nil {
+		log.Error("Error retrieving contact form schema: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving settings"})
+		return
+	}
+
+	github, err := utils.GetGitHubUserSettings()
+	if err != nil {
+		log.Error("Error retrieving GitHub user settings: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving settings"})
+		return
+	}
+
+	c.JSON(200, gin.H{"autoReply": autoReply, "contactForm": contactForm, "github": github})
+}
+
+func UpdateAutoReplySettings(c *gin.Context) {
+	var request struct {
+		Enabled *bool  `json:"enabled" binding:"required"`
+		Subject string `json:"subject"`
+		Body    string `json:"body"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings := utils.AutoReplySettings{Enabled: *request.Enabled, Subject: request.Subject, Body: request.Body}
+
+	if err := settings.Validate(); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid auto-reply template", "fullError": err.Error()})
+		return
+	}
+
+	if err := utils.SaveSetting(utils.AUTO_REPLY_SETTING, settings); err != nil {
+		log.Error("Error saving auto-reply settings: ", err)
+		c.JSON(500, gin.H{"error": "Error saving settings"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Auto-reply updated successfully", "autoReply": settings})
+}
+
+func UpdateContactFormSchema(c *gin.Context) {
+	var request struct {
+		Fields []utils.ContactFormField `json:"fields" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	schema := utils.ContactFormSchema{Fields: request.Fields}
+
+	if err := schema.Validate(); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid contact form schema", "fullError": err.Error()})
+		return
+	}
+
+	if err := utils.SaveSetting(utils.CONTACT_FORM_SETTING, schema); err != nil {
+		log.Error("Error saving contact form schema: ", err)
+		c.JSON(500, gin.H{"error": "Error saving settings"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Contact form updated successfully", "contactForm": schema})
+}
+
+func UpdateGitHubUserSettings(c *gin.Context) {
+	var request struct {
+		AllowedUsers []string `json:"allowedUsers" binding:"required"`
+		DefaultUser  string   `json:"defaultUser"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings := utils.GitHubUserSettings{AllowedUsers: request.AllowedUsers, DefaultUser: request.DefaultUser}
+
+	if err := settings.Validate(); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid GitHub users", "fullError": err.Error()})
+		return
+	}
+
+	if err := utils.SaveSetting(utils.GITHUB_USERS_SETTING, settings); err != nil {
+		log.Error("Error saving GitHub user settings: ", err)
+		c.JSON(500, gin.H{"error": "Error saving settings"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "GitHub users updated successfully", "github": settings})
+}
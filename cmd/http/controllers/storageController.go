@@ -1,13 +1,21 @@
 package controllers
 
 import (
+	"errors"
+	"io"
 	"net/http"
-	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	"strings"
+
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/storage"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 )
 
+const maxUploadRequestSize = 50 << 20
+
 func CreatePresignedURL(c *gin.Context) {
 	var request struct {
 		UploadCategory structs.UploadCategory `json:"uploadCategory" binding:"required"` // Use structs.UploadCategory
@@ -18,11 +26,156 @@ func CreatePresignedURL(c *gin.Context) {
 		return
 	}
 
-	url, err := utils.GeneratePresignedPost(initializers.S3Session, request.UploadCategory)
+	url, key, err := utils.GeneratePresignedPost(initializers.Storage, request.UploadCategory)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating presigned URL"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"url": url})
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{"url": url, "key": key, "publicURL": initializers.Storage.PublicURL(key)})
+}
+
+// Called once a presigned upload has finished, registers it in the media library and
+// resolves it to the existing object if the same content was uploaded before
+func ConfirmUpload(c *gin.Context) {
+	var request struct {
+		Key string `json:"key" binding:"required"` // Object key or storage URL
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, err := utils.ResolveStorageKey(initializers.Storage, request.Key)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload key", "fullError": err.Error()})
+		return
+	}
+
+	asset, deduplicated, err := utils.ConfirmUpload(initializers.Storage, key)
+	if err != nil {
+		log.Error("Error confirming upload: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error confirming upload"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"media": asset, "deduplicated": deduplicated})
+}
+
+// Server-side alternative to presigned URLs for clients that cannot PUT to storage directly.
+// The multipart body is streamed to storage, so the "category" field must come before the "file" part
+// (or be passed as the ?category= query parameter).
+func UploadFile(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadRequestSize)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Expected a multipart/form-data upload"})
+		return
+	}
+
+	category := structs.UploadCategory(c.Query("category"))
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error reading upload", "fullError": err.Error()})
+			return
+		}
+
+		switch part.FormName() {
+		case "category":
+			value, err := io.ReadAll(io.LimitReader(part, 64))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Error reading upload category"})
+				return
+			}
+			category = structs.UploadCategory(value)
+		case "file":
+			if category == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "UploadCategory is required before the file"})
+				return
+			}
+
+			asset, deduplicated, err := utils.StreamUpload(initializers.Storage, category, part)
+			if err != nil {
+				switch {
+				case errors.Is(err, utils.ErrUploadTooLarge):
+					c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+				case errors.Is(err, utils.ErrUploadTypeNotAllowed), errors.Is(err, utils.ErrUnknownUploadCategory):
+					c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+				default:
+					log.Error("Error streaming upload: ", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Error storing upload"})
+				}
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{"key": asset.ObjectKey, "url": asset.URL, "media": asset, "deduplicated": deduplicated})
+			return
+		}
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "No file was uploaded"})
+}
+
+// Upload target for presigned URLs handed out by the local storage driver
+func LocalStorageUpload(c *gin.Context) {
+	localStorage, key, ok := verifyLocalStorageRequest(c, http.MethodPut)
+	if !ok {
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadRequestSize)
+	if err := localStorage.Put(key, body, c.ContentType()); err != nil {
+		log.Error("Error storing local upload: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error storing upload"})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Serves objects stored by the local storage driver through signed URLs
+func LocalStorageDownload(c *gin.Context) {
+	localStorage, key, ok := verifyLocalStorageRequest(c, http.MethodGet)
+	if !ok {
+		return
+	}
+
+	objectInfo, err := localStorage.Head(key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Object not found"})
+		return
+	}
+
+	reader, err := localStorage.Open(key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Object not found"})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, objectInfo.Size, objectInfo.ContentType, reader, map[string]string{
+		"Cache-Control": "private, max-age=3600",
+	})
+}
+
+func verifyLocalStorageRequest(c *gin.Context, method string) (*storage.LocalStorage, string, bool) {
+	localStorage, isLocal := initializers.Storage.(*storage.LocalStorage)
+	if !isLocal {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Local storage is not enabled"})
+		return nil, "", false
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := localStorage.VerifySignature(method, key, c.Query("expires"), c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid storage signature", "fullError": err.Error()})
+		return nil, "", false
+	}
+
+	return localStorage, key, true
+}
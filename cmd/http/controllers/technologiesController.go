@@ -5,6 +5,8 @@ import (
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 func CreateTechnology(c *gin.Context) {
@@ -20,8 +22,9 @@ func CreateTechnology(c *gin.Context) {
 		return
 	}
 
-	// Validate technologyImage URL
-	if err := utils.ValidateS3URL(initializers.S3Session, newTechnology.TechnologyImage); err != nil {
+	// Validate technologyImage (object key or storage URL)
+	technologyImageKey, err := utils.ResolveStorageKey(initializers.Storage, newTechnology.TechnologyImage)
+	if err != nil {
 		c.JSON(400, gin.H{"error": "Invalid technologyImage URL", "fullError": err.Error()})
 		return
 	}
@@ -36,19 +39,35 @@ func CreateTechnology(c *gin.Context) {
 	}
 
 	technology := structs.Technologies{
-		TechnologyName:  newTechnology.TechnologyName,
-		TechnologyType:  newTechnology.TechnologyType,
-		TechnologyImage: newTechnology.TechnologyImage,
+		TechnologyName:     newTechnology.TechnologyName,
+		TechnologyType:     newTechnology.TechnologyType,
+		TechnologyImageKey: technologyImageKey,
 	}
 
-	result = initializers.DB.Create(&technology)
+	// Register the image in the media library once the technology is saved, a duplicate upload is merged into the existing asset
+	var mergedKeys []string
+	err = initializers.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&technology).Error; err != nil {
+			return err
+		}
 
-	if result.Error != nil {
+		mergedKeys, err = utils.RegisterMediaReferences(tx, initializers.Storage, technologyImageKey)
+		return err
+	})
+
+	if err != nil {
+		log.Error("Error creating technology: ", err)
 		c.JSON(500, gin.H{"error": "Error creating technology"})
 		return
 	}
 
+	utils.DeleteMediaObjects(initializers.Storage, mergedKeys)
+
 	// Get value of the inserted technology
+	if err := initializers.DB.First(&technology, technology.ID).Error; err != nil {
+		c.JSON(500, gin.H{"error": "Error retrieving technology"})
+		return
+	}
 
 	c.JSON(200, gin.H{"message": "Technology created successfully", "technology": technology})
 }
@@ -75,8 +94,9 @@ func UpdateTechnology(c *gin.Context) {
 		return
 	}
 
-	// Validate technologyImage URL
-	if err := utils.ValidateS3URL(initializers.S3Session, updatedTechnology.TechnologyImage); err != nil {
+	// Validate technologyImage (object key or storage URL)
+	technologyImageKey, err := utils.ResolveStorageKey(initializers.Storage, updatedTechnology.TechnologyImage)
+	if err != nil {
 		c.JSON(400, gin.H{"error": "Invalid technologyImage URL", "fullError": err.Error()})
 		return
 	}
@@ -88,17 +108,34 @@ func UpdateTechnology(c *gin.Context) {
 	}
 
 	technology := structs.Technologies{
-		TechnologyName:  updatedTechnology.TechnologyName,
-		TechnologyType:  updatedTechnology.TechnologyType,
-		TechnologyImage: updatedTechnology.TechnologyImage,
-	}
-	// Update the technology with the provided ID
-	if err := initializers.DB.Model(&existingTechnology).Updates(technology).Error; err != nil {
+		TechnologyName:     updatedTechnology.TechnologyName,
+		TechnologyType:     updatedTechnology.TechnologyType,
+		TechnologyImageKey: technologyImageKey,
+	}
+	// Update the technology with the provided ID, then register its image in the media library
+	var mergedKeys []string
+	err = initializers.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&existingTechnology).Updates(technology).Error; err != nil {
+			return err
+		}
+
+		mergedKeys, err = utils.RegisterMediaReferences(tx, initializers.Storage, technologyImageKey)
+		return err
+	})
+
+	if err != nil {
+		log.Error("Error updating technology: ", err)
 		c.JSON(500, gin.H{"error": "Error updating technology"})
 		return
 	}
 
+	utils.DeleteMediaObjects(initializers.Storage, mergedKeys)
+
 	// Get value of the updated technology
+	if err := initializers.DB.First(&technology, existingTechnology.ID).Error; err != nil {
+		c.JSON(500, gin.H{"error": "Error retrieving technology"})
+		return
+	}
 
 	c.JSON(200, gin.H{"message": "Technology updated successfully", "technology": technology})
 }
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// GitHub caps webhook payloads at 25 MB, anything we react to is far smaller
+const maxWebhookPayloadSize = 5 << 20
+
+func GitHubWebhook(c *gin.Context) {
+
+	if len(initializers.GitHubWebhookSecret) == 0 {
+		c.JSON(404, gin.H{"error": "GitHub webhooks are not enabled"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookPayloadSize+1))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Error reading payload"})
+		return
+	}
+
+	if len(body) > maxWebhookPayloadSize {
+		c.JSON(413, gin.H{"error": "Payload too large"})
+		return
+	}
+
+	if !github.VerifyWebhookSignature(initializers.GitHubWebhookSecret, body, c.GetHeader("X-Hub-Signature-256")) {
+		c.JSON(401, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	deliveryID := c.GetHeader("X-GitHub-Delivery")
+	event := c.GetHeader("X-GitHub-Event")
+	if deliveryID == "" || event == "" {
+		c.JSON(400, gin.H{"error": "Missing X-GitHub-Delivery or X-GitHub-Event header"})
+		return
+	}
+
+	// Deliveries are processed once, but a failed one can be redelivered from GitHub to retry it
+	var delivery structs.GitHubWebhookDeliveries
+	err = initializers.DB.Where("delivery_id = ?", deliveryID).First(&delivery).Error
+	if err == nil && delivery.Status != structs.WEBHOOK_FAILED {
+		c.JSON(200, gin.H{"message": "Delivery already processed", "status": delivery.Status})
+		return
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error finding webhook delivery: ", err)
+		c.JSON(500, gin.H{"error": "Error storing delivery"})
+		return
+	}
+
+	delivery.DeliveryID = deliveryID
+	delivery.Event = event
+	delivery.Payload = string(body)
+	delivery.Status = structs.WEBHOOK_RECEIVED
+
+	if err := initializers.DB.Save(&delivery).Error; err != nil {
+		// Lost a race with a concurrent delivery of the same ID
+		if initializers.DB.Where("delivery_id = ?", deliveryID).First(&structs.GitHubWebhookDeliveries{}).Error == nil {
+			c.JSON(200, gin.H{"message": "Delivery already processed"})
+			return
+		}
+		log.Error("Error storing webhook delivery: ", err)
+		c.JSON(500, gin.H{"error": "Error storing delivery"})
+		return
+	}
+
+	if err := utils.ProcessGitHubWebhook(&delivery); err != nil {
+		log.Error("Error processing GitHub webhook ", deliveryID, ": ", err)
+		c.JSON(500, gin.H{"error": "Error processing delivery", "status": delivery.Status})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Delivery processed", "status": delivery.Status, "reactions": delivery.Reactions})
+}
+
+func GetGitHubWebhookDeliveries(c *gin.Context) {
+
+	page, pageSize := getPagination(c)
+
+	query := initializers.DB.Model(&structs.GitHubWebhookDeliveries{})
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if event := c.Query("event"); event != "" {
+		query = query.Where("event = ?", event)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error("Error counting webhook deliveries: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving webhook deliveries"})
+		return
+	}
+
+	var deliveries []structs.GitHubWebhookDeliveries
+	if err := query.Omit("payload").Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&deliveries).Error; err != nil {
+		log.Error("Error retrieving webhook deliveries: ", err)
+		c.JSON(500, gin.H{"error": "Error retrieving webhook deliveries"})
+		return
+	}
+
+	c.JSON(200, gin.H{"deliveries": deliveries, "pagination": structs.PaginationModel{Page: page, PageSize: pageSize, Total: total}})
+}
+
+func GetGitHubWebhookDelivery(c *gin.Context) {
+
+	deliveryID := c.Param("deliveryID")
+
+	var delivery structs.GitHubWebhookDeliveries
+	if err := initializers.DB.First(&delivery, "id = ?", deliveryID).Error; err != nil {
+		c.JSON(404, gin.H{"error": "No webhook delivery found with this ID"})
+		return
+	}
+
+	c.JSON(200, gin.H{"delivery": delivery, "payload": json.RawMessage(delivery.Payload)})
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/ratelimit"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// RateLimitMiddleware limits requests with the named policy from initializers.RateLimitPolicies,
+// disabled policies let every request through
+func RateLimitMiddleware(policyName string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, enabled := initializers.RateLimitPolicies[policyName]
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		result, err := initializers.RateLimiter.Take(rateLimitKey(c, policy.KeyBy), policy)
+		if err != nil {
+			// Fail open, an unavailable store shouldn't take the site down with it
+			log.Error("Error checking rate limit: ", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(result.Reset))
+		c.Header("RateLimit-Policy", strconv.Itoa(policy.Limit)+";w="+ceilSeconds(policy.Period))
+
+		if !result.Allowed {
+			c.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please try again later"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func rateLimitKey(c *gin.Context, keyBy ratelimit.KeyBy) string {
+	switch keyBy {
+	case ratelimit.KEY_BY_USER:
+		tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if tokenString != "" {
+			if userId, err := utils.ValidateToken(tokenString); err == nil {
+				return "user:" + strconv.FormatUint(uint64(userId), 10)
+			}
+		}
+	case ratelimit.KEY_BY_API_KEY:
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			// Keys are hashed so they never end up in the store
+			sum := sha256.Sum256([]byte(apiKey))
+			return "key:" + hex.EncodeToString(sum[:16])
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/Jake4-CX/portfolio-website-v2-backend/cmd/http/controllers"
 	middlewares "github.com/Jake4-CX/portfolio-website-v2-backend/cmd/http/middleware"
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/mailer"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/storage"
 	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,14 +19,28 @@ func main() {
 
 	initializers.LoadEnvVariables()
 	initializers.InitializeDB()
-	initializers.InitializeS3()
+	initializers.InitializeStorage()
+	initializers.MigrateStorageKeys()
+	initializers.InitializeMailer()
+	initializers.InitializeCaptcha()
+	initializers.InitializeSpamFilter()
+	initializers.InitializeRateLimiter()
+	initializers.InitializeCache()
+	initializers.InitializeGitHub()
+	initializers.InitializeContributionProviders()
+
+	utils.StartEmailWorker()
+	utils.StartNotificationWorker()
+	utils.StartRepositorySyncWorker()
+	utils.StartWebhookPruneWorker()
 
 	router := gin.Default()
+	initializers.ConfigureTrustedProxies(router)
 
 	router.Use(GinMiddleware(("*")))
 
-	router.POST("/auth/register", controllers.CreateUser)
-	router.POST("/auth/login", controllers.LoginUser)
+	router.POST("/auth/register", middlewares.RateLimitMiddleware("register"), controllers.CreateUser)
+	router.POST("/auth/login", middlewares.RateLimitMiddleware("login"), controllers.LoginUser)
 	router.POST("/auth/validate", controllers.ValidateUserAccessToken)
 	router.POST("/auth/refresh", controllers.RefreshAccessToken)
 
@@ -34,12 +51,38 @@ func main() {
 	// Projects
 	router.GET("/projects", controllers.GetProjects)
 	router.GET("/projects/:projectID", controllers.GetProject)
+	router.GET("/projects/:projectID/activity", middlewares.RateLimitMiddleware("github"), controllers.GetProjectActivity)
+	router.GET("/activity", middlewares.RateLimitMiddleware("github"), controllers.GetActivity)
 
 	// GitHub
-	router.GET("/github/commits", controllers.GetCommitHistory)
+	router.GET("/github/commits", middlewares.RateLimitMiddleware("github"), controllers.GetCommitHistory)
+	router.GET("/github/commits.svg", middlewares.RateLimitMiddleware("github"), controllers.GetCommitHistorySVG)
+	router.GET("/github/stats", middlewares.RateLimitMiddleware("github"), controllers.GetContributionStats)
+	router.GET("/github/repositories/pinned", middlewares.RateLimitMiddleware("github"), controllers.GetPinnedRepositories)
+	router.GET("/github/languages", middlewares.RateLimitMiddleware("github"), controllers.GetLanguageBreakdown)
+
+	// Contributions (GitHub, GitLab and Gitea merged)
+	router.GET("/contributions", middlewares.RateLimitMiddleware("github"), controllers.GetMergedContributions)
 
 	// Contact
-	router.POST("/contact", controllers.ContactEmail)
+	router.GET("/contact/schema", controllers.GetContactFormSchema)
+	router.GET("/contact/token", controllers.GetContactFormToken)
+	router.POST("/contact", middlewares.RateLimitMiddleware("contact"), controllers.ContactEmail)
+
+	// Webhooks
+	router.POST("/webhooks/github", controllers.GitHubWebhook)
+
+	// Storage (signed URLs issued by the local storage driver)
+	router.PUT(storage.LocalRoutePrefix+"*key", controllers.LocalStorageUpload)
+	router.GET(storage.LocalRoutePrefix+"*key", controllers.LocalStorageDownload)
+
+	// Captured mail (development mailer only), admins only as it holds every email the API sent
+	if _, ok := initializers.Mailer.(*mailer.DevMailer); ok {
+		dev := router.Group("/dev", middlewares.RoleMiddleware(structs.ADMIN))
+		dev.GET("/mail", controllers.GetCapturedMail)
+		dev.GET("/mail/:messageID", controllers.GetCapturedMailMessage)
+		dev.DELETE("/mail", controllers.ClearCapturedMail)
+	}
 
 	authorized := router.Group("/")
 
@@ -55,9 +98,57 @@ func main() {
 		authorized.PUT("/projects/:projectID", controllers.UpdateProject)
 		authorized.PUT("/projects/:projectID/images", controllers.AssignProjectImages)
 		authorized.DELETE("/projects/:projectID", controllers.DeleteProject)
+		authorized.POST("/projects/:projectID/repository/sync", controllers.ResyncProjectRepository)
+		authorized.GET("/projects/:projectID/technology-suggestions", controllers.GetProjectTechnologySuggestions)
 
 		// Storage
 		authorized.POST("/storage/create-presigned-url", controllers.CreatePresignedURL)
+		authorized.POST("/storage/confirm-upload", controllers.ConfirmUpload)
+		authorized.POST("/storage/upload", controllers.UploadFile)
+
+		// Media library
+		authorized.GET("/media", controllers.GetMedia)
+		authorized.GET("/media/duplicates", controllers.GetDuplicateMedia)
+		authorized.POST("/media/duplicates/merge", controllers.MergeDuplicateMedia)
+		authorized.GET("/media/:mediaID", controllers.GetMediaAsset)
+		authorized.DELETE("/media/:mediaID", controllers.DeleteMediaAsset)
+
+		// Contact inbox
+		authorized.GET("/contact/messages", controllers.GetContactMessages)
+		authorized.GET("/contact/messages/:messageID", controllers.GetContactMessage)
+		authorized.PUT("/contact/messages/:messageID/read", controllers.MarkContactMessageRead)
+		authorized.PUT("/contact/messages/:messageID/archive", controllers.ArchiveContactMessage)
+		authorized.PUT("/contact/messages/:messageID/star", controllers.StarContactMessage)
+		authorized.PUT("/contact/messages/:messageID/spam", controllers.MarkContactMessageSpam)
+		authorized.POST("/contact/messages/:messageID/reply", controllers.ReplyToContactMessage)
+		authorized.DELETE("/contact/messages/:messageID", controllers.DeleteContactMessage)
+
+		// Email outbox
+		authorized.GET("/emails/outbox", controllers.GetOutboundEmails)
+		authorized.GET("/emails/outbox/:emailID", controllers.GetOutboundEmail)
+		authorized.POST("/emails/outbox/:emailID/requeue", controllers.RequeueOutboundEmail)
+		authorized.GET("/emails/templates", controllers.GetEmailTemplates)
+		authorized.GET("/emails/templates/:templateName/preview", controllers.PreviewEmailTemplate)
+
+		// Settings
+		authorized.GET("/settings", controllers.GetSettings)
+		authorized.PUT("/settings/auto-reply", controllers.UpdateAutoReplySettings)
+		authorized.PUT("/settings/contact-form", controllers.UpdateContactFormSchema)
+		authorized.PUT("/settings/github", controllers.UpdateGitHubUserSettings)
+
+		// Webhooks
+		authorized.GET("/webhooks/github/deliveries", controllers.GetGitHubWebhookDeliveries)
+		authorized.GET("/webhooks/github/deliveries/:deliveryID", controllers.GetGitHubWebhookDelivery)
+
+		// Notifications
+		authorized.GET("/notifications/channels", controllers.GetNotificationChannels)
+		authorized.POST("/notifications/channels", controllers.CreateNotificationChannel)
+		authorized.GET("/notifications/channels/:channelID", controllers.GetNotificationChannel)
+		authorized.PUT("/notifications/channels/:channelID", controllers.UpdateNotificationChannel)
+		authorized.DELETE("/notifications/channels/:channelID", controllers.DeleteNotificationChannel)
+		authorized.POST("/notifications/channels/:channelID/test", controllers.TestNotificationChannel)
+		authorized.GET("/notifications/deliveries", controllers.GetNotificationDeliveries)
+		authorized.POST("/notifications/deliveries/:deliveryID/requeue", controllers.RequeueNotificationDelivery)
 	}
 
 	log.Fatal(router.Run("0.0.0.0:" + os.Getenv("REST_PORT")))
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// One-time backfill: registers every object in the bucket in the media library, stores the
+// content hash of each asset and (with -merge) rewrites references of duplicates to a single object
+func main() {
+	merge := flag.Bool("merge", false, "merge duplicate objects after hashing")
+	flag.Parse()
+
+	initializers.LoadEnvVariables()
+	initializers.InitializeDB()
+	initializers.InitializeStorage()
+	initializers.MigrateStorageKeys()
+
+	if err := utils.SyncMediaLibrary(initializers.Storage, true); err != nil {
+		log.Fatal("Error syncing media library: ", err)
+	}
+
+	hashed, err := utils.BackfillMediaHashes(initializers.Storage)
+	if err != nil {
+		log.Fatal("Error backfilling media hashes: ", err)
+	}
+	log.Infof("Hashed %d media assets", hashed)
+
+	groups, err := utils.FindDuplicateMedia()
+	if err != nil {
+		log.Fatal("Error finding duplicate media: ", err)
+	}
+
+	for _, group := range groups {
+		log.Infof("%s: %s has %d duplicate(s)", group.ContentHash, group.Canonical.ObjectKey, len(group.Duplicates))
+	}
+
+	if !*merge {
+		log.Infof("Found %d duplicate group(s), run with -merge to merge them", len(groups))
+		return
+	}
+
+	merged, err := utils.MergeDuplicateMedia(initializers.Storage)
+	if err != nil {
+		log.Fatal("Error merging duplicate media: ", err)
+	}
+	log.Infof("Merged %d duplicate media assets", merged)
+}
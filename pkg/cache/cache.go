@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Status string
+
+const (
+	HIT   Status = "HIT"
+	MISS  Status = "MISS"
+	STALE Status = "STALE"
+)
+
+type Entry struct {
+	Value    []byte
+	StoredAt time.Time
+}
+
+// Store is implemented by every cache backend (memory, database, Redis), Get returns nil for a miss
+type Store interface {
+	Get(key string) (*Entry, error)
+	Set(key string, entry Entry, expiresIn time.Duration) error
+	Delete(key string) error
+}
+
+// Cache serves entries younger than TTL straight from the store. Older entries are served
+// stale while a background refresh runs (for up to StaleTTL), and whenever the loader fails.
+type Cache struct {
+	store    Store
+	ttl      time.Duration
+	staleTTL time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]*call
+}
+
+type call struct {
+	done  chan struct{}
+	entry *Entry
+	err   error
+}
+
+// Result is a cached value with its cache status and age, for the X-Cache and Age headers
+type Result struct {
+	Value  []byte
+	Status Status
+	Age    time.Duration
+	// MaxAge is how much longer the value is fresh for
+	MaxAge time.Duration
+}
+
+func New(store Store, ttl time.Duration, staleTTL time.Duration) *Cache {
+	return &Cache{store: store, ttl: ttl, staleTTL: staleTTL, inflight: make(map[string]*call)}
+}
+
+// Fetch returns the cached value for key, calling loader on a miss or to revalidate a stale value
+func (c *Cache) Fetch(key string, loader func() ([]byte, error)) (*Result, error) {
+	entry, err := c.store.Get(key)
+	if err != nil {
+		log.Warn("Error reading cache entry ", key, ": ", err)
+		entry = nil
+	}
+
+	if entry != nil {
+		age := time.Since(entry.StoredAt)
+
+		if age < c.ttl {
+			return c.result(entry, HIT), nil
+		}
+
+		if age < c.ttl+c.staleTTL {
+			go c.load(key, loader)
+			return c.result(entry, STALE), nil
+		}
+	}
+
+	fresh, err := c.load(key, loader)
+	if err != nil {
+		if entry != nil {
+			log.Warn("Serving stale cache entry ", key, " after refresh failed: ", err)
+			return c.result(entry, STALE), nil
+		}
+		return nil, err
+	}
+
+	return c.result(fresh, MISS), nil
+}
+
+// load calls loader and stores the value, concurrent loads of the same key share one call
+func (c *Cache) load(key string, loader func() ([]byte, error)) (*Entry, error) {
+	c.mu.Lock()
+	if existing, loading := c.inflight[key]; loading {
+		c.mu.Unlock()
+		<-existing.done
+		return existing.entry, existing.err
+	}
+
+	current := &call{done: make(chan struct{})}
+	c.inflight[key] = current
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(current.done)
+	}()
+
+	value, err := loader()
+	if err != nil {
+		current.err = err
+		return nil, err
+	}
+
+	current.entry = &Entry{Value: value, StoredAt: time.Now()}
+	if err := c.store.Set(key, *current.entry, c.ttl+c.staleTTL); err != nil {
+		log.Warn("Error storing cache entry ", key, ": ", err)
+	}
+
+	return current.entry, nil
+}
+
+// Invalidate drops the keys, so the next Fetch loads them again instead of serving them stale
+func (c *Cache) Invalidate(keys ...string) error {
+	for _, key := range keys {
+		if err := c.store.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache) result(entry *Entry, status Status) *Result {
+	age := time.Since(entry.StoredAt)
+
+	maxAge := c.ttl - age
+	if maxAge < 0 {
+		maxAge = 0
+	}
+
+	return &Result{Value: entry.Value, Status: status, Age: age, MaxAge: maxAge}
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DatabaseStore struct {
+	db *gorm.DB
+}
+
+func NewDatabaseStore(db *gorm.DB) *DatabaseStore {
+	return &DatabaseStore{db: db}
+}
+
+func (s *DatabaseStore) Get(key string) (*Entry, error) {
+	var entry structs.CacheEntries
+	err := s.db.Where("cache_key = ? AND expires_at > ?", key, time.Now()).First(&entry).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &Entry{Value: entry.Value, StoredAt: entry.StoredAt}, nil
+}
+
+func (s *DatabaseStore) Set(key string, entry Entry, expiresIn time.Duration) error {
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cache_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "stored_at", "expires_at"}),
+	}).Create(&structs.CacheEntries{
+		CacheKey:  key,
+		Value:     entry.Value,
+		StoredAt:  entry.StoredAt,
+		ExpiresAt: entry.StoredAt.Add(expiresIn),
+	}).Error
+}
+
+func (s *DatabaseStore) Delete(key string) error {
+	return s.db.Where("cache_key = ?", key).Delete(&structs.CacheEntries{}).Error
+}
+
+// DeleteExpired removes entries that can no longer be served
+func (s *DatabaseStore) DeleteExpired() error {
+	return s.db.Where("expires_at <= ?", time.Now()).Delete(&structs.CacheEntries{}).Error
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type MemoryStore struct {
+	mu        sync.RWMutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	Entry
+	expiresAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), lastSweep: time.Now()}
+}
+
+func (s *MemoryStore) Get(key string) (*Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.entries[key]
+	if !exists || time.Now().After(entry.expiresAt) {
+		return nil, nil
+	}
+
+	return &entry.Entry, nil
+}
+
+func (s *MemoryStore) Set(key string, entry Entry, expiresIn time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.entries[key] = memoryEntry{Entry: entry, expiresAt: now.Add(expiresIn)}
+
+	// Forget expired entries every so often
+	if now.Sub(s.lastSweep) > time.Minute {
+		s.lastSweep = now
+		for key, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps entries in Redis (or a compatible server) as "<stored at unix nanos>|<value>"
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Get(key string) (*Entry, error) {
+	value, err := s.client.Get(context.Background(), s.prefix+key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	storedAt, data, found := strings.Cut(value, "|")
+	if !found {
+		return nil, nil
+	}
+
+	nanos, err := strconv.ParseInt(storedAt, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+
+	return &Entry{Value: []byte(data), StoredAt: time.Unix(0, nanos)}, nil
+}
+
+func (s *RedisStore) Set(key string, entry Entry, expiresIn time.Duration) error {
+	value := strconv.FormatInt(entry.StoredAt.UnixNano(), 10) + "|" + string(entry.Value)
+	return s.client.Set(context.Background(), s.prefix+key, value, expiresIn).Err()
+}
+
+func (s *RedisStore) Delete(key string) error {
+	return s.client.Del(context.Background(), s.prefix+key).Err()
+}
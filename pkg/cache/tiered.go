@@ -0,0 +1,46 @@
+package cache
+
+import "time"
+
+// TieredStore reads through a fast store (memory) to a persistent store (database, Redis), so
+// a restart doesn't mean a cold cache
+type TieredStore struct {
+	fast       Store
+	persistent Store
+}
+
+func NewTieredStore(fast Store, persistent Store) *TieredStore {
+	return &TieredStore{fast: fast, persistent: persistent}
+}
+
+func (s *TieredStore) Get(key string) (*Entry, error) {
+	entry, err := s.fast.Get(key)
+	if err != nil || entry != nil {
+		return entry, err
+	}
+
+	entry, err = s.persistent.Get(key)
+	if err != nil || entry == nil {
+		return entry, err
+	}
+
+	// The persistent store doesn't tell us when the entry expires, so keep it in the
+	// fast store briefly and let the persistent store stay authoritative
+	s.fast.Set(key, *entry, time.Minute)
+
+	return entry, nil
+}
+
+func (s *TieredStore) Set(key string, entry Entry, expiresIn time.Duration) error {
+	if err := s.fast.Set(key, entry, expiresIn); err != nil {
+		return err
+	}
+	return s.persistent.Set(key, entry, expiresIn)
+}
+
+func (s *TieredStore) Delete(key string) error {
+	if err := s.fast.Delete(key); err != nil {
+		return err
+	}
+	return s.persistent.Delete(key)
+}
@@ -0,0 +1,155 @@
+package captcha
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrVerificationFailed = errors.New("captcha verification failed")
+	ErrScoreTooLow        = errors.New("captcha score is below the minimum")
+	ErrActionMismatch     = errors.New("captcha action does not match")
+	ErrHostnameNotAllowed = errors.New("captcha was solved on a hostname that is not allowed")
+)
+
+type Result struct {
+	Success  bool     `json:"success"`
+	Score    float64  `json:"score"`
+	Action   string   `json:"action"`
+	Hostname string   `json:"hostname"`
+	Errors   []string `json:"errors"`
+	Provider string   `json:"provider"`
+}
+
+// CaptchaVerifier is implemented by every captcha provider (reCAPTCHA, hCaptcha, Turnstile).
+// Verify returns the decoded result alongside any rejection, so the score can be logged either way.
+type CaptchaVerifier interface {
+	Verify(token string, remoteIP string) (*Result, error)
+}
+
+type Config struct {
+	SecretKey string
+	// VerifyURL overrides the provider's siteverify endpoint (e.g. a local stub in tests)
+	VerifyURL string
+	// MinScore only applies to providers that return a score
+	MinScore         float64
+	ExpectedAction   string
+	AllowedHostnames []string
+}
+
+// siteVerifier implements the siteverify protocol, which all supported providers share
+type siteVerifier struct {
+	provider  string
+	verifyURL string
+	config    Config
+	client    *http.Client
+	// checkScore is false for providers that do not return a comparable score
+	checkScore  bool
+	checkAction bool
+	// mapScore converts the provider's score into a 0 (bot) to 1 (human) score
+	mapScore func(score float64) float64
+}
+
+func newSiteVerifier(provider string, defaultURL string, config Config) *siteVerifier {
+	verifyURL := config.VerifyURL
+	if verifyURL == "" {
+		verifyURL = defaultURL
+	}
+
+	return &siteVerifier{
+		provider:  provider,
+		verifyURL: verifyURL,
+		config:    config,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (v *siteVerifier) Verify(token string, remoteIP string) (*Result, error) {
+	if token == "" {
+		return nil, ErrVerificationFailed
+	}
+
+	payload := url.Values{
+		"secret":   {v.config.SecretKey},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		payload.Set("remoteip", remoteIP)
+	}
+
+	resp, err := v.client.PostForm(v.verifyURL, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %v", v.provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", v.provider, resp.StatusCode)
+	}
+
+	var response struct {
+		Success    bool     `json:"success"`
+		Hostname   string   `json:"hostname"`
+		Score      *float64 `json:"score"`
+		Action     string   `json:"action"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %v", v.provider, err)
+	}
+
+	result := &Result{
+		Success:  response.Success,
+		Action:   response.Action,
+		Hostname: response.Hostname,
+		Errors:   response.ErrorCodes,
+		Provider: v.provider,
+	}
+
+	if response.Score != nil {
+		result.Score = *response.Score
+		if v.mapScore != nil {
+			result.Score = v.mapScore(result.Score)
+		}
+	}
+
+	return result, v.check(result, response.Score != nil)
+}
+
+func (v *siteVerifier) check(result *Result, hasScore bool) error {
+	if !result.Success {
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("%w: %s", ErrVerificationFailed, strings.Join(result.Errors, ", "))
+		}
+		return ErrVerificationFailed
+	}
+
+	if v.checkScore && hasScore && result.Score < v.config.MinScore {
+		return fmt.Errorf("%w (%.2f < %.2f)", ErrScoreTooLow, result.Score, v.config.MinScore)
+	}
+
+	if v.checkAction && v.config.ExpectedAction != "" && result.Action != v.config.ExpectedAction {
+		return fmt.Errorf("%w (expected %q, got %q)", ErrActionMismatch, v.config.ExpectedAction, result.Action)
+	}
+
+	if len(v.config.AllowedHostnames) > 0 && !hostnameAllowed(v.config.AllowedHostnames, result.Hostname) {
+		return fmt.Errorf("%w (%q)", ErrHostnameNotAllowed, result.Hostname)
+	}
+
+	return nil
+}
+
+func hostnameAllowed(allowed []string, hostname string) bool {
+	for _, allowedHostname := range allowed {
+		if strings.EqualFold(strings.TrimSpace(allowedHostname), hostname) {
+			return true
+		}
+	}
+	return false
+}
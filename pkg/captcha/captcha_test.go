@@ -0,0 +1,151 @@
+package captcha
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newSiteverifyStub answers every siteverify request with the response, recording the posted form
+func newSiteverifyStub(t *testing.T, response map[string]interface{}) (*httptest.Server, *http.Request) {
+	t.Helper()
+
+	received := &http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		*received = *r
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, received
+}
+
+func TestProviders(t *testing.T) {
+	providers := []struct {
+		name string
+		new  func(Config) CaptchaVerifier
+		// score is what the provider returns for a likely human, hCaptcha returns a risk score
+		humanScore, botScore float64
+		checksScore          bool
+		checksAction         bool
+	}{
+		{"recaptcha_v2", NewRecaptchaV2, 0.9, 0.1, false, false},
+		{"recaptcha_v3", NewRecaptchaV3, 0.9, 0.1, true, true},
+		{"hcaptcha", NewHCaptcha, 0.1, 0.9, true, false},
+		{"turnstile", NewTurnstile, 0.9, 0.1, false, true},
+	}
+
+	for _, provider := range providers {
+		cases := []struct {
+			name     string
+			response map[string]interface{}
+			wantErr  error
+		}{
+			{
+				name:     "success",
+				response: map[string]interface{}{"success": true, "score": provider.humanScore, "action": "contact", "hostname": "example.com"},
+			},
+			{
+				name:     "failure",
+				response: map[string]interface{}{"success": false, "error-codes": []string{"invalid-input-response"}},
+				wantErr:  ErrVerificationFailed,
+			},
+			{
+				name:     "score mismatch",
+				response: map[string]interface{}{"success": true, "score": provider.botScore, "action": "contact", "hostname": "example.com"},
+				wantErr:  ErrScoreTooLow,
+			},
+			{
+				name:     "action mismatch",
+				response: map[string]interface{}{"success": true, "score": provider.humanScore, "action": "login", "hostname": "example.com"},
+				wantErr:  ErrActionMismatch,
+			},
+		}
+
+		for _, test := range cases {
+			t.Run(provider.name+"/"+test.name, func(t *testing.T) {
+				server, received := newSiteverifyStub(t, test.response)
+
+				verifier := provider.new(Config{
+					SecretKey:      "secret",
+					VerifyURL:      server.URL,
+					MinScore:       0.5,
+					ExpectedAction: "contact",
+				})
+
+				result, err := verifier.Verify("token", "203.0.113.7")
+
+				wantErr := test.wantErr
+				if (wantErr == ErrScoreTooLow && !provider.checksScore) || (wantErr == ErrActionMismatch && !provider.checksAction) {
+					// The provider doesn't enforce this check
+					wantErr = nil
+				}
+
+				if wantErr == nil && err != nil {
+					t.Fatalf("Verify() error = %v, want nil", err)
+				}
+				if wantErr != nil && !errors.Is(err, wantErr) {
+					t.Fatalf("Verify() error = %v, want %v", err, wantErr)
+				}
+
+				if result == nil || result.Provider != provider.name {
+					t.Fatalf("Verify() result = %+v, want the %s result", result, provider.name)
+				}
+				if result.Success != test.response["success"] {
+					t.Errorf("result.Success = %v, want %v", result.Success, test.response["success"])
+				}
+
+				if got := received.PostForm; got.Get("secret") != "secret" || got.Get("response") != "token" || got.Get("remoteip") != "203.0.113.7" {
+					t.Errorf("posted form = %v", got)
+				}
+			})
+		}
+	}
+}
+
+func TestHCaptchaScoreIsInverted(t *testing.T) {
+	server, _ := newSiteverifyStub(t, map[string]interface{}{"success": true, "score": 0.2})
+
+	result, err := NewHCaptcha(Config{VerifyURL: server.URL, MinScore: 0.5}).Verify("token", "")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if result.Score != 0.8 {
+		t.Errorf("result.Score = %v, want 0.8", result.Score)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	t.Run("empty token", func(t *testing.T) {
+		if _, err := NewTurnstile(Config{VerifyURL: "http://127.0.0.1:0"}).Verify("", ""); !errors.Is(err, ErrVerificationFailed) {
+			t.Errorf("Verify() error = %v, want %v", err, ErrVerificationFailed)
+		}
+	})
+
+	t.Run("hostname not allowed", func(t *testing.T) {
+		server, _ := newSiteverifyStub(t, map[string]interface{}{"success": true, "hostname": "evil.example"})
+
+		verifier := NewRecaptchaV2(Config{VerifyURL: server.URL, AllowedHostnames: []string{"example.com"}})
+		if _, err := verifier.Verify("token", ""); !errors.Is(err, ErrHostnameNotAllowed) {
+			t.Errorf("Verify() error = %v, want %v", err, ErrHostnameNotAllowed)
+		}
+	})
+
+	t.Run("provider error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		if _, err := NewRecaptchaV3(Config{VerifyURL: server.URL}).Verify("token", ""); err == nil {
+			t.Error("Verify() error = nil, want an error")
+		}
+	})
+}
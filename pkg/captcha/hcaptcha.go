@@ -0,0 +1,12 @@
+package captcha
+
+const hcaptchaVerifyURL = "https://api.hcaptcha.com/siteverify"
+
+// NewHCaptcha verifies hCaptcha tokens. Only enterprise accounts return a score, which is a
+// risk score (1 is a bot), so it is inverted to match the reCAPTCHA v3 direction.
+func NewHCaptcha(config Config) CaptchaVerifier {
+	verifier := newSiteVerifier("hcaptcha", hcaptchaVerifyURL, config)
+	verifier.checkScore = true
+	verifier.mapScore = func(score float64) float64 { return 1 - score }
+	return verifier
+}
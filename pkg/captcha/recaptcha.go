@@ -0,0 +1,16 @@
+package captcha
+
+const recaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+
+// NewRecaptchaV2 verifies checkbox / invisible reCAPTCHA tokens, which have no score or action
+func NewRecaptchaV2(config Config) CaptchaVerifier {
+	return newSiteVerifier("recaptcha_v2", recaptchaVerifyURL, config)
+}
+
+// NewRecaptchaV3 verifies score based reCAPTCHA tokens, enforcing the minimum score and expected action
+func NewRecaptchaV3(config Config) CaptchaVerifier {
+	verifier := newSiteVerifier("recaptcha_v3", recaptchaVerifyURL, config)
+	verifier.checkScore = true
+	verifier.checkAction = true
+	return verifier
+}
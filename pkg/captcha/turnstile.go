@@ -0,0 +1,10 @@
+package captcha
+
+const turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+
+// NewTurnstile verifies Cloudflare Turnstile tokens, the action is the widget's data-action
+func NewTurnstile(config Config) CaptchaVerifier {
+	verifier := newSiteVerifier("turnstile", turnstileVerifyURL, config)
+	verifier.checkAction = true
+	return verifier
+}
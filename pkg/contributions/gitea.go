@@ -0,0 +1,51 @@
+package contributions
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GiteaProvider reads the profile heatmap of Gitea and Forgejo, which only covers the last 12 months,
+// so earlier years come back empty
+type GiteaProvider struct {
+	baseURL    string
+	header     http.Header
+	httpClient *http.Client
+}
+
+func NewGiteaProvider(baseURL string, token string, timeout time.Duration) *GiteaProvider {
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "token "+token)
+	}
+
+	return &GiteaProvider{baseURL: strings.TrimSuffix(baseURL, "/"), header: header, httpClient: &http.Client{Timeout: timeout}}
+}
+
+func (p *GiteaProvider) Name() string {
+	return "gitea"
+}
+
+func (p *GiteaProvider) Contributions(ctx context.Context, login string, year int) (map[string]int, error) {
+	var heatmap []struct {
+		Timestamp     int64 `json:"timestamp"`
+		Contributions int   `json:"contributions"`
+	}
+
+	if _, err := getJSON(ctx, p.httpClient, p.baseURL+"/api/v1/users/"+url.PathEscape(login)+"/heatmap", p.header, &heatmap); err != nil {
+		return nil, err
+	}
+
+	days := make(map[string]int)
+	for _, entry := range heatmap {
+		date := time.Unix(entry.Timestamp, 0).UTC()
+		if year == 0 || date.Year() == year {
+			days[date.Format(dateLayout)] += entry.Contributions
+		}
+	}
+
+	return days, nil
+}
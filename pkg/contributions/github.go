@@ -0,0 +1,36 @@
+package contributions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+)
+
+type GitHubProvider struct {
+	client *github.Client
+}
+
+func NewGitHubProvider(client *github.Client) *GitHubProvider {
+	return &GitHubProvider{client: client}
+}
+
+func (p *GitHubProvider) Name() string {
+	return "github"
+}
+
+func (p *GitHubProvider) Contributions(ctx context.Context, login string, year int) (map[string]int, error) {
+	collection, err := p.client.GetContributions(ctx, login, year)
+	if errors.Is(err, github.ErrNotFound) {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	days := make(map[string]int)
+	for _, day := range collection.ContributionCalendar.Days() {
+		days[day.Date] = day.ContributionCount
+	}
+
+	return days, nil
+}
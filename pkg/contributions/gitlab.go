@@ -0,0 +1,74 @@
+package contributions
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxGitLabEventPages caps a year's events at 10,000
+const maxGitLabEventPages = 100
+
+// GitLabProvider reads the profile calendar for the last 12 months, and counts the user's events for other years
+type GitLabProvider struct {
+	baseURL    string
+	header     http.Header
+	httpClient *http.Client
+}
+
+// NewGitLabProvider defaults to gitlab.com, the token (optional for public profiles) needs read_api
+func NewGitLabProvider(baseURL string, token string, timeout time.Duration) *GitLabProvider {
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+
+	header := http.Header{}
+	if token != "" {
+		header.Set("PRIVATE-TOKEN", token)
+	}
+
+	return &GitLabProvider{baseURL: strings.TrimSuffix(baseURL, "/"), header: header, httpClient: &http.Client{Timeout: timeout}}
+}
+
+func (p *GitLabProvider) Name() string {
+	return "gitlab"
+}
+
+func (p *GitLabProvider) Contributions(ctx context.Context, login string, year int) (map[string]int, error) {
+	if year == 0 {
+		days := make(map[string]int)
+		_, err := getJSON(ctx, p.httpClient, p.baseURL+"/users/"+url.PathEscape(login)+"/calendar.json", p.header, &days)
+		return days, err
+	}
+
+	query := url.Values{}
+	query.Set("after", time.Date(year-1, time.December, 31, 0, 0, 0, 0, time.UTC).Format(dateLayout))
+	query.Set("before", time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC).Format(dateLayout))
+	query.Set("per_page", "100")
+
+	days := make(map[string]int)
+	for page := 1; page <= maxGitLabEventPages; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		var events []struct {
+			CreatedAt time.Time `json:"created_at"`
+		}
+		header, err := getJSON(ctx, p.httpClient, p.baseURL+"/api/v4/users/"+url.PathEscape(login)+"/events?"+query.Encode(), p.header, &events)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			days[event.CreatedAt.UTC().Format(dateLayout)]++
+		}
+
+		if header.Get("X-Next-Page") == "" || len(events) == 0 {
+			break
+		}
+	}
+
+	return days, nil
+}
@@ -0,0 +1,206 @@
+package contributions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+)
+
+var ErrUserNotFound = errors.New("contributions: user not found")
+
+const dateLayout = "2006-01-02"
+
+// Colors are GitHub's light theme colours for no contributions up to the fourth quartile
+var Colors = [5]string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+var levels = [5]string{"NONE", "FIRST_QUARTILE", "SECOND_QUARTILE", "THIRD_QUARTILE", "FOURTH_QUARTILE"}
+
+// Provider is a code host that can report a user's daily contribution counts
+type Provider interface {
+	Name() string
+	// Contributions returns the user's contributions per day (YYYY-MM-DD) in the year, or the last 12 months when year is 0
+	Contributions(ctx context.Context, login string, year int) (map[string]int, error)
+}
+
+// Account is a user on a provider
+type Account struct {
+	Provider Provider
+	Login    string
+}
+
+// AccountResult is one account's share of a merged calendar
+type AccountResult struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	Total    int    `json:"total"`
+}
+
+// Merge sums the daily counts of every account. A calendar missing an account would be cached as if it were
+// complete, so any account failing fails the merge with every account's error
+func Merge(ctx context.Context, accounts []Account, year int) (map[string]int, []AccountResult, error) {
+	merged := make(map[string]int)
+	results := make([]AccountResult, len(accounts))
+
+	type response struct {
+		days map[string]int
+		err  error
+	}
+	responses := make([]response, len(accounts))
+
+	done := make(chan struct{})
+	for i, account := range accounts {
+		go func(i int, account Account) {
+			days, err := account.Provider.Contributions(ctx, account.Login, year)
+			responses[i] = response{days: days, err: err}
+			done <- struct{}{}
+		}(i, account)
+	}
+	for range accounts {
+		<-done
+	}
+
+	var errs []error
+	for i, account := range accounts {
+		results[i] = AccountResult{Provider: account.Provider.Name(), Login: account.Login}
+
+		if err := responses[i].err; err != nil {
+			errs = append(errs, fmt.Errorf("%s contributions for %s: %w", results[i].Provider, account.Login, err))
+			continue
+		}
+
+		for date, count := range responses[i].days {
+			merged[date] += count
+			results[i].Total += count
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, results, errors.Join(errs...)
+	}
+
+	return merged, results, nil
+}
+
+// BuildCalendar lays daily counts out like GitHub's contribution calendar: weeks starting on Sunday, covering
+// the year (up to today) or the last 52 weeks when year is 0, with quartile colours
+func BuildCalendar(days map[string]int, year int, now time.Time) github.ContributionCalendar {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	from := today.AddDate(0, 0, -7*52-int(today.Weekday()))
+	to := today
+	if year != 0 {
+		from = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to = time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+		if to.After(today) {
+			to = today
+		}
+	}
+
+	maxCount := 0
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		if count := days[date.Format(dateLayout)]; count > maxCount {
+			maxCount = count
+		}
+	}
+
+	calendar := github.ContributionCalendar{Weeks: []github.ContributionWeek{}}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		if len(calendar.Weeks) == 0 || date.Weekday() == time.Sunday {
+			calendar.Weeks = append(calendar.Weeks, github.ContributionWeek{})
+		}
+
+		count := days[date.Format(dateLayout)]
+		level := 0
+		if count > 0 {
+			level = int(math.Ceil(float64(count) / float64(maxCount) * 4))
+		}
+
+		week := &calendar.Weeks[len(calendar.Weeks)-1]
+		week.ContributionDays = append(week.ContributionDays, github.ContributionDay{
+			Weekday:           int(date.Weekday()),
+			Date:              date.Format(dateLayout),
+			ContributionCount: count,
+			Color:             Colors[level],
+			ContributionLevel: levels[level],
+		})
+		calendar.TotalContributions += count
+	}
+
+	return calendar
+}
+
+// Years returns the years with at least one contribution, newest first like GitHub's contributionYears
+func Years(days map[string]int) []int {
+	seen := make(map[int]bool)
+	years := []int{}
+	for date, count := range days {
+		parsed, err := time.Parse(dateLayout, date)
+		if err != nil || count == 0 || seen[parsed.Year()] {
+			continue
+		}
+		seen[parsed.Year()] = true
+		years = append(years, parsed.Year())
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(years)))
+	return years
+}
+
+// MergeYears combines lists of contribution years, newest first
+func MergeYears(lists ...[]int) []int {
+	seen := make(map[int]bool)
+	years := []int{}
+	for _, list := range lists {
+		for _, year := range list {
+			if !seen[year] {
+				seen[year] = true
+				years = append(years, year)
+			}
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(years)))
+	return years
+}
+
+// getJSON fetches url and decodes the response into out, 404s are ErrUserNotFound
+func getJSON(ctx context.Context, client *http.Client, url string, header http.Header, out interface{}) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrUserNotFound
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%s returned %d: %s", req.URL.Host, resp.StatusCode, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("failed to decode response from %s: %v", req.URL.Host, err)
+	}
+
+	return resp.Header, nil
+}
@@ -0,0 +1,146 @@
+package github
+
+import (
+	"context"
+	"time"
+)
+
+type Actor struct {
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatarUrl"`
+}
+
+type Commit struct {
+	Oid             string    `json:"oid"`
+	AbbreviatedOid  string    `json:"abbreviatedOid"`
+	MessageHeadline string    `json:"messageHeadline"`
+	URL             string    `json:"url"`
+	CommittedDate   time.Time `json:"committedDate"`
+	Author          struct {
+		Name string `json:"name"`
+		User *Actor `json:"user"`
+	} `json:"author"`
+}
+
+type ReleaseActivity struct {
+	Release
+	IsPrerelease bool   `json:"isPrerelease"`
+	Author       *Actor `json:"author"`
+}
+
+type PullRequest struct {
+	Number   int       `json:"number"`
+	Title    string    `json:"title"`
+	URL      string    `json:"url"`
+	MergedAt time.Time `json:"mergedAt"`
+	Author   *Actor    `json:"author"`
+}
+
+// RepositoryActivity is the most recent commits on the default branch, published releases and merged pull requests
+type RepositoryActivity struct {
+	IsPrivate    bool              `json:"isPrivate"`
+	Commits      []Commit          `json:"commits"`
+	Releases     []ReleaseActivity `json:"releases"`
+	PullRequests []PullRequest     `json:"pullRequests"`
+}
+
+const repositoryActivityQuery = `
+	query ($owner: String!, $name: String!, $first: Int!) {
+		repository(owner: $owner, name: $name) {
+			isPrivate
+			defaultBranchRef {
+				target {
+					... on Commit {
+						history(first: $first) {
+							nodes {
+								oid
+								abbreviatedOid
+								messageHeadline
+								url
+								committedDate
+								author {
+									name
+									user { login avatarUrl }
+								}
+							}
+						}
+					}
+				}
+			}
+			releases(first: $first, orderBy: {field: CREATED_AT, direction: DESC}) {
+				nodes {
+					tagName
+					name
+					url
+					publishedAt
+					isPrerelease
+					isDraft
+					author { login avatarUrl }
+				}
+			}
+			pullRequests(first: $first, states: MERGED, orderBy: {field: UPDATED_AT, direction: DESC}) {
+				nodes {
+					number
+					title
+					url
+					mergedAt
+					author { login avatarUrl }
+				}
+			}
+		}
+	}
+`
+
+// GetRepositoryActivity returns up to first (at most 100) of each kind of recent activity in the repository
+func (c *Client) GetRepositoryActivity(ctx context.Context, owner string, name string, first int) (*RepositoryActivity, error) {
+	var data struct {
+		Repository *struct {
+			IsPrivate        bool `json:"isPrivate"`
+			DefaultBranchRef *struct {
+				Target struct {
+					History struct {
+						Nodes []Commit `json:"nodes"`
+					} `json:"history"`
+				} `json:"target"`
+			} `json:"defaultBranchRef"`
+			Releases struct {
+				Nodes []struct {
+					ReleaseActivity
+					IsDraft bool `json:"isDraft"`
+				} `json:"nodes"`
+			} `json:"releases"`
+			PullRequests struct {
+				Nodes []PullRequest `json:"nodes"`
+			} `json:"pullRequests"`
+		} `json:"repository"`
+	}
+
+	variables := map[string]interface{}{"owner": owner, "name": name, "first": first}
+	if err := c.GraphQL(ctx, repositoryActivityQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Repository == nil {
+		return nil, ErrNotFound
+	}
+
+	activity := &RepositoryActivity{
+		IsPrivate:    data.Repository.IsPrivate,
+		Commits:      []Commit{},
+		Releases:     []ReleaseActivity{},
+		PullRequests: data.Repository.PullRequests.Nodes,
+	}
+
+	// Empty repositories have no default branch
+	if data.Repository.DefaultBranchRef != nil {
+		activity.Commits = data.Repository.DefaultBranchRef.Target.History.Nodes
+	}
+
+	for _, release := range data.Repository.Releases.Nodes {
+		if !release.IsDraft {
+			activity.Releases = append(activity.Releases, release.ReleaseActivity)
+		}
+	}
+
+	return activity, nil
+}
@@ -0,0 +1,159 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultBaseURL = "https://api.github.com"
+
+type Config struct {
+	// BaseURL defaults to https://api.github.com, GraphQL requests go to BaseURL + "/graphql"
+	BaseURL string
+	Token   string
+	Timeout time.Duration
+}
+
+// Client is a small typed GitHub API client covering the REST and GraphQL endpoints the site uses
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+
+	mu        sync.RWMutex
+	rateLimit RateLimit
+}
+
+// RateLimit is the quota reported by the most recent response
+type RateLimit struct {
+	Resource  string    `json:"resource"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	Reset     time.Time `json:"reset"`
+}
+
+func NewClient(config Config) *Client {
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 15 * time.Second
+	}
+
+	return &Client{
+		baseURL:    baseURL,
+		token:      config.Token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) RateLimit() RateLimit {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rateLimit
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors GraphQLErrors   `json:"errors"`
+}
+
+// GraphQL runs the query and decodes its data into out
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	var response graphQLResponse
+	if err := c.do(ctx, http.MethodPost, "/graphql", bytes.NewReader(body), &response); err != nil {
+		return err
+	}
+
+	if len(response.Errors) > 0 {
+		return response.Errors
+	}
+
+	return json.Unmarshal(response.Data, out)
+}
+
+// REST sends a request to the REST API (path is relative to the base URL) and decodes the response into out
+func (c *Client) REST(ctx context.Context, method string, path string, out interface{}) error {
+	return c.do(ctx, method, path, nil, out)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	c.trackRateLimit(resp.Header)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode GitHub response: %v", err)
+	}
+
+	return nil
+}
+
+func (c *Client) trackRateLimit(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	used, _ := strconv.Atoi(header.Get("X-RateLimit-Used"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+
+	c.mu.Lock()
+	c.rateLimit = RateLimit{
+		Resource:  header.Get("X-RateLimit-Resource"),
+		Limit:     limit,
+		Remaining: remaining,
+		Used:      used,
+		Reset:     time.Unix(reset, 0),
+	}
+	c.mu.Unlock()
+}
@@ -0,0 +1,229 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newTestClient points a client at a stub server running handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{BaseURL: server.URL + "/", Token: "token"})
+}
+
+func TestGraphQLDecodesData(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("request = %s %s, want POST /graphql", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q", got)
+		}
+
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		if request.Query != "query { viewer { login } }" || request.Variables["login"] != "octocat" {
+			t.Errorf("request = %+v", request)
+		}
+
+		io.WriteString(w, `{"data": {"viewer": {"login": "octocat", "followers": 42}}}`)
+	})
+
+	var data struct {
+		Viewer struct {
+			Login     string `json:"login"`
+			Followers int    `json:"followers"`
+		} `json:"viewer"`
+	}
+
+	err := client.GraphQL(context.Background(), "query { viewer { login } }", map[string]interface{}{"login": "octocat"}, &data)
+	if err != nil {
+		t.Fatalf("GraphQL() error = %v", err)
+	}
+	if data.Viewer.Login != "octocat" || data.Viewer.Followers != 42 {
+		t.Errorf("data = %+v", data)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		errorType string
+		want      error
+		notWant   error
+	}{
+		{"not found", "NOT_FOUND", ErrNotFound, ErrRateLimited},
+		{"rate limited", "RATE_LIMITED", ErrRateLimited, ErrNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {"user": null}, "errors": [
+					{"type": "FORBIDDEN", "message": "forbidden"},
+					{"type": "`+test.errorType+`", "message": "something went wrong", "path": ["user"]}
+				]}`)
+			})
+
+			var data struct{}
+			err := client.GraphQL(context.Background(), "query { user }", nil, &data)
+
+			var graphQLErrs GraphQLErrors
+			if !errors.As(err, &graphQLErrs) || len(graphQLErrs) != 2 {
+				t.Fatalf("GraphQL() error = %#v, want both GraphQLErrors", err)
+			}
+			if !errors.Is(err, test.want) {
+				t.Errorf("errors.Is(err, %v) = false", test.want)
+			}
+			if errors.Is(err, test.notWant) || errors.Is(err, ErrUnauthorized) {
+				t.Errorf("err %v matches an unrelated error", err)
+			}
+			if err.Error() != "github: forbidden; something went wrong" {
+				t.Errorf("Error() = %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	reset := time.Now().Add(90 * time.Second).Unix()
+
+	tests := []struct {
+		name           string
+		status         int
+		headers        map[string]string
+		body           string
+		want           error
+		wantMessage    string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:        "not found",
+			status:      http.StatusNotFound,
+			body:        `{"message": "Not Found"}`,
+			want:        ErrNotFound,
+			wantMessage: "Not Found",
+		},
+		{
+			name:        "unauthorized",
+			status:      http.StatusUnauthorized,
+			body:        `{"message": "Bad credentials"}`,
+			want:        ErrUnauthorized,
+			wantMessage: "Bad credentials",
+		},
+		{
+			name:           "primary rate limit",
+			status:         http.StatusForbidden,
+			headers:        map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(reset, 10)},
+			body:           `{"message": "API rate limit exceeded"}`,
+			want:           ErrRateLimited,
+			wantMessage:    "API rate limit exceeded",
+			wantRetryAfter: 90 * time.Second,
+		},
+		{
+			name:           "secondary rate limit",
+			status:         http.StatusTooManyRequests,
+			headers:        map[string]string{"Retry-After": "30"},
+			want:           ErrRateLimited,
+			wantMessage:    "Too Many Requests",
+			wantRetryAfter: 30 * time.Second,
+		},
+		{
+			name:        "forbidden without a rate limit",
+			status:      http.StatusForbidden,
+			headers:     map[string]string{"X-RateLimit-Remaining": "12"},
+			body:        "not json",
+			wantMessage: "Forbidden",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				for key, value := range test.headers {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(test.status)
+				io.WriteString(w, test.body)
+			})
+
+			err := client.REST(context.Background(), http.MethodGet, "/users/octocat", nil)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("REST() error = %#v, want an *APIError", err)
+			}
+			if apiErr.StatusCode != test.status || apiErr.Message != test.wantMessage {
+				t.Errorf("APIError = %d %q, want %d %q", apiErr.StatusCode, apiErr.Message, test.status, test.wantMessage)
+			}
+
+			for _, target := range []error{ErrNotFound, ErrUnauthorized, ErrRateLimited} {
+				if got := errors.Is(err, target); got != (target == test.want) {
+					t.Errorf("errors.Is(err, %v) = %v", target, got)
+				}
+			}
+
+			// The reset is whole seconds away, so allow for the time the test takes
+			if diff := apiErr.RetryAfter - test.wantRetryAfter; diff > 0 || diff < -2*time.Second {
+				t.Errorf("RetryAfter = %v, want %v", apiErr.RetryAfter, test.wantRetryAfter)
+			}
+			if RetryAfter(err, RateLimit{}) != apiErr.RetryAfter {
+				t.Errorf("RetryAfter(err) = %v, want %v", RetryAfter(err, RateLimit{}), apiErr.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestTrackRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/untracked" {
+			// Responses without rate limit headers keep the last known quota
+			io.WriteString(w, `{}`)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Resource", "graphql")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4990")
+		w.Header().Set("X-RateLimit-Used", "10")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		io.WriteString(w, `{}`)
+	})
+
+	if client.RateLimit() != (RateLimit{}) {
+		t.Fatalf("RateLimit() before any request = %+v", client.RateLimit())
+	}
+
+	want := RateLimit{Resource: "graphql", Limit: 5000, Remaining: 4990, Used: 10, Reset: reset}
+	for _, path := range []string{"/tracked", "/untracked"} {
+		if err := client.REST(context.Background(), http.MethodGet, path, nil); err != nil {
+			t.Fatalf("REST(%s) error = %v", path, err)
+		}
+
+		if got := client.RateLimit(); got.Resource != want.Resource || got.Limit != want.Limit || got.Remaining != want.Remaining ||
+			got.Used != want.Used || !got.Reset.Equal(want.Reset) {
+			t.Errorf("RateLimit() after %s = %+v, want %+v", path, got, want)
+		}
+	}
+
+	// GraphQL rate limits report no Retry-After, so the tracked reset time is used
+	rateLimited := GraphQLErrors{{Type: "RATE_LIMITED", Message: "API rate limit exceeded"}}
+	if wait := RetryAfter(rateLimited, client.RateLimit()); wait <= 59*time.Minute || wait > time.Hour {
+		t.Errorf("RetryAfter() = %v, want about an hour", wait)
+	}
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"context"
+	"time"
+)
+
+type ContributionDay struct {
+	Weekday           int    `json:"weekday"`
+	Date              string `json:"date"`
+	ContributionCount int    `json:"contributionCount"`
+	Color             string `json:"color"`
+	// ContributionLevel is NONE or FIRST_QUARTILE to FOURTH_QUARTILE
+	ContributionLevel string `json:"contributionLevel"`
+}
+
+type ContributionWeek struct {
+	ContributionDays []ContributionDay `json:"contributionDays"`
+}
+
+type ContributionCalendar struct {
+	TotalContributions int                `json:"totalContributions"`
+	Weeks              []ContributionWeek `json:"weeks"`
+}
+
+type ContributionsCollection struct {
+	ContributionYears    []int                `json:"contributionYears"`
+	ContributionCalendar ContributionCalendar `json:"contributionCalendar"`
+}
+
+const contributionsQuery = `
+	query ($login: String!, $from: DateTime, $to: DateTime) {
+		user(login: $login) {
+			contributionsCollection(from: $from, to: $to) {
+				contributionYears
+				contributionCalendar {
+					totalContributions
+					weeks {
+						contributionDays {
+							weekday
+							date
+							contributionCount
+							color
+							contributionLevel
+						}
+					}
+				}
+			}
+		}
+	}
+`
+
+// GetContributions returns the user's contribution calendar for the year, or the last 12 months when year is 0
+func (c *Client) GetContributions(ctx context.Context, login string, year int) (*ContributionsCollection, error) {
+	variables := map[string]interface{}{"login": login}
+	if year != 0 {
+		variables["from"] = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+		variables["to"] = time.Date(year, time.December, 31, 23, 59, 59, 0, time.UTC).Format(time.RFC3339)
+	}
+
+	var data struct {
+		User *struct {
+			ContributionsCollection ContributionsCollection `json:"contributionsCollection"`
+		} `json:"user"`
+	}
+
+	if err := c.GraphQL(ctx, contributionsQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	if data.User == nil {
+		return nil, ErrNotFound
+	}
+
+	return &data.User.ContributionsCollection, nil
+}
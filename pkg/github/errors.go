@@ -0,0 +1,114 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound     = errors.New("github: not found")
+	ErrUnauthorized = errors.New("github: bad or missing access token")
+	ErrRateLimited  = errors.New("github: rate limit exceeded")
+)
+
+// APIError is a non-2xx REST or GraphQL response, errors.Is matches it against the errors above
+type APIError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is set when rate limited
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("github: %d %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.RetryAfter > 0 || e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 4096)); err == nil && json.Unmarshal(data, &body) == nil && body.Message != "" {
+		apiErr.Message = body.Message
+	}
+
+	// Primary limits send remaining 0 & a reset time, secondary limits send Retry-After
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		} else if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+			apiErr.RetryAfter = time.Until(time.Unix(reset, 0))
+			if apiErr.RetryAfter <= 0 {
+				apiErr.RetryAfter = time.Second
+			}
+		}
+	}
+
+	return apiErr
+}
+
+type GraphQLError struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+// GraphQLErrors are returned with a 200 status, errors.Is maps NOT_FOUND & RATE_LIMITED
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, graphQLErr := range e {
+		messages = append(messages, graphQLErr.Message)
+	}
+	return "github: " + strings.Join(messages, "; ")
+}
+
+func (e GraphQLErrors) Is(target error) bool {
+	for _, graphQLErr := range e {
+		switch {
+		case target == ErrNotFound && graphQLErr.Type == "NOT_FOUND":
+			return true
+		case target == ErrRateLimited && graphQLErr.Type == "RATE_LIMITED":
+			return true
+		}
+	}
+	return false
+}
+
+// RetryAfter returns how long to wait before retrying a rate limited request, 0 if err isn't one
+func RetryAfter(err error, rateLimit RateLimit) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	if errors.Is(err, ErrRateLimited) {
+		if wait := time.Until(rateLimit.Reset); wait > 0 {
+			return wait
+		}
+		return time.Minute
+	}
+
+	return 0
+}
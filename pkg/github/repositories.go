@@ -0,0 +1,272 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+type Language struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type Repository struct {
+	Name            string    `json:"name"`
+	NameWithOwner   string    `json:"nameWithOwner"`
+	Description     string    `json:"description"`
+	URL             string    `json:"url"`
+	HomepageURL     string    `json:"homepageUrl"`
+	StargazerCount  int       `json:"stargazerCount"`
+	ForkCount       int       `json:"forkCount"`
+	PrimaryLanguage *Language `json:"primaryLanguage"`
+	PushedAt        time.Time `json:"pushedAt"`
+	IsArchived      bool      `json:"isArchived"`
+}
+
+type LanguageTotal struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	Bytes int64  `json:"bytes"`
+	// Percentage of all bytes across the repositories, to two decimal places
+	Percentage   float64 `json:"percentage"`
+	Repositories int     `json:"repositories"`
+}
+
+const repositoryFields = `
+	name
+	nameWithOwner
+	description
+	url
+	homepageUrl
+	stargazerCount
+	forkCount
+	primaryLanguage { name color }
+	pushedAt
+	isArchived
+`
+
+const pinnedRepositoriesQuery = `
+	query ($login: String!) {
+		user(login: $login) {
+			pinnedItems(first: 6, types: REPOSITORY) {
+				nodes {
+					... on Repository {` + repositoryFields + `}
+				}
+			}
+		}
+	}
+`
+
+// GetPinnedRepositories returns the repositories pinned to the user's profile, in profile order
+func (c *Client) GetPinnedRepositories(ctx context.Context, login string) ([]Repository, error) {
+	var data struct {
+		User *struct {
+			PinnedItems struct {
+				Nodes []Repository `json:"nodes"`
+			} `json:"pinnedItems"`
+		} `json:"user"`
+	}
+
+	if err := c.GraphQL(ctx, pinnedRepositoriesQuery, map[string]interface{}{"login": login}, &data); err != nil {
+		return nil, err
+	}
+
+	if data.User == nil {
+		return nil, ErrNotFound
+	}
+
+	return data.User.PinnedItems.Nodes, nil
+}
+
+const repositoryLanguagesQuery = `
+	query ($login: String!, $cursor: String) {
+		user(login: $login) {
+			repositories(first: 100, after: $cursor, ownerAffiliations: OWNER, isFork: false) {
+				pageInfo { hasNextPage endCursor }
+				nodes {
+					languages(first: 20, orderBy: {field: SIZE, direction: DESC}) {
+						edges {
+							size
+							node { name color }
+						}
+					}
+				}
+			}
+		}
+	}
+`
+
+// maxRepositoryPages caps the language breakdown at the first 1,000 repositories
+const maxRepositoryPages = 10
+
+// GetLanguageBreakdown sums the bytes of each language across the user's own (non-fork) repositories, largest first
+func (c *Client) GetLanguageBreakdown(ctx context.Context, login string) ([]LanguageTotal, error) {
+	totals := make(map[string]*LanguageTotal)
+	var allBytes int64
+
+	var cursor *string
+	for page := 0; page < maxRepositoryPages; page++ {
+		var data struct {
+			User *struct {
+				Repositories struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						Languages struct {
+							Edges []struct {
+								Size int64    `json:"size"`
+								Node Language `json:"node"`
+							} `json:"edges"`
+						} `json:"languages"`
+					} `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"user"`
+		}
+
+		if err := c.GraphQL(ctx, repositoryLanguagesQuery, map[string]interface{}{"login": login, "cursor": cursor}, &data); err != nil {
+			return nil, err
+		}
+
+		if data.User == nil {
+			return nil, ErrNotFound
+		}
+
+		for _, repository := range data.User.Repositories.Nodes {
+			for _, edge := range repository.Languages.Edges {
+				total, ok := totals[edge.Node.Name]
+				if !ok {
+					total = &LanguageTotal{Name: edge.Node.Name, Color: edge.Node.Color}
+					totals[edge.Node.Name] = total
+				}
+				total.Bytes += edge.Size
+				total.Repositories++
+				allBytes += edge.Size
+			}
+		}
+
+		pageInfo := data.User.Repositories.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		cursor = &pageInfo.EndCursor
+	}
+
+	languages := make([]LanguageTotal, 0, len(totals))
+	for _, total := range totals {
+		if allBytes > 0 {
+			total.Percentage = round(float64(total.Bytes) / float64(allBytes) * 100)
+		}
+		languages = append(languages, *total)
+	}
+
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Bytes != languages[j].Bytes {
+			return languages[i].Bytes > languages[j].Bytes
+		}
+		return languages[i].Name < languages[j].Name
+	})
+
+	return languages, nil
+}
+
+type Release struct {
+	TagName     string    `json:"tagName"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+type License struct {
+	SpdxID string `json:"spdxId"`
+	Name   string `json:"name"`
+}
+
+// RepositoryDetails is the metadata synced onto projects linked to a repository
+type RepositoryDetails struct {
+	Repository
+	IsPrivate     bool       `json:"isPrivate"`
+	OpenIssues    int        `json:"openIssues"`
+	LastCommitAt  *time.Time `json:"lastCommitAt"`
+	LatestRelease *Release   `json:"latestRelease"`
+	License       *License   `json:"license"`
+	Topics        []string   `json:"topics"`
+}
+
+const repositoryDetailsQuery = `
+	query ($owner: String!, $name: String!) {
+		repository(owner: $owner, name: $name) {` + repositoryFields + `
+			isPrivate
+			issues(states: OPEN) { totalCount }
+			defaultBranchRef {
+				target {
+					... on Commit { committedDate }
+				}
+			}
+			latestRelease { tagName name url publishedAt }
+			licenseInfo { spdxId name }
+			repositoryTopics(first: 20) {
+				nodes {
+					topic { name }
+				}
+			}
+		}
+	}
+`
+
+// GetRepository returns the repository's stats, latest release, licence and topics
+func (c *Client) GetRepository(ctx context.Context, owner string, name string) (*RepositoryDetails, error) {
+	var data struct {
+		Repository *struct {
+			Repository
+			IsPrivate bool `json:"isPrivate"`
+			Issues    struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"issues"`
+			DefaultBranchRef *struct {
+				Target struct {
+					CommittedDate *time.Time `json:"committedDate"`
+				} `json:"target"`
+			} `json:"defaultBranchRef"`
+			LatestRelease    *Release `json:"latestRelease"`
+			LicenseInfo      *License `json:"licenseInfo"`
+			RepositoryTopics struct {
+				Nodes []struct {
+					Topic struct {
+						Name string `json:"name"`
+					} `json:"topic"`
+				} `json:"nodes"`
+			} `json:"repositoryTopics"`
+		} `json:"repository"`
+	}
+
+	if err := c.GraphQL(ctx, repositoryDetailsQuery, map[string]interface{}{"owner": owner, "name": name}, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Repository == nil {
+		return nil, ErrNotFound
+	}
+
+	details := &RepositoryDetails{
+		Repository:    data.Repository.Repository,
+		IsPrivate:     data.Repository.IsPrivate,
+		OpenIssues:    data.Repository.Issues.TotalCount,
+		LatestRelease: data.Repository.LatestRelease,
+		License:       data.Repository.LicenseInfo,
+		Topics:        make([]string, 0, len(data.Repository.RepositoryTopics.Nodes)),
+	}
+
+	// Empty repositories have no default branch
+	if data.Repository.DefaultBranchRef != nil {
+		details.LastCommitAt = data.Repository.DefaultBranchRef.Target.CommittedDate
+	}
+
+	for _, node := range data.Repository.RepositoryTopics.Nodes {
+		details.Topics = append(details.Topics, node.Topic.Name)
+	}
+
+	return details, nil
+}
@@ -0,0 +1,195 @@
+package github
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type Streak struct {
+	Length int    `json:"length"`
+	Start  string `json:"start,omitempty"`
+	End    string `json:"end,omitempty"`
+}
+
+type YearTotal struct {
+	Year  int `json:"year"`
+	Total int `json:"total"`
+}
+
+type MonthTotal struct {
+	Month string `json:"month"`
+	Total int    `json:"total"`
+}
+
+type WeekdayTotal struct {
+	Weekday int     `json:"weekday"`
+	Name    string  `json:"name"`
+	Total   int     `json:"total"`
+	Average float64 `json:"average"`
+}
+
+type BusiestDay struct {
+	Date              string `json:"date"`
+	ContributionCount int    `json:"contributionCount"`
+}
+
+type ContributionStats struct {
+	TotalContributions int            `json:"totalContributions"`
+	FirstContribution  string         `json:"firstContribution,omitempty"`
+	CurrentStreak      Streak         `json:"currentStreak"`
+	LongestStreak      Streak         `json:"longestStreak"`
+	Years              []YearTotal    `json:"years"`
+	Months             []MonthTotal   `json:"months"`
+	Weekdays           []WeekdayTotal `json:"weekdays"`
+	BusiestDay         *BusiestDay    `json:"busiestDay"`
+	// RollingAverage is the mean daily contributions over the 30 days up to today
+	RollingAverage float64 `json:"rollingAverage"`
+}
+
+// Days flattens the calendar into its days, oldest first
+func (calendar ContributionCalendar) Days() []ContributionDay {
+	var days []ContributionDay
+	for _, week := range calendar.Weeks {
+		days = append(days, week.ContributionDays...)
+	}
+	return days
+}
+
+// ComputeStats summarises contribution days (from any number of calendars, duplicates are ignored).
+// Days after now are dropped, with enough slack that users ahead of UTC keep their today.
+func ComputeStats(days []ContributionDay, now time.Time) ContributionStats {
+	cutoff := now.UTC().Add(14 * time.Hour).Format(dateLayout)
+
+	byDate := make(map[string]ContributionDay, len(days))
+	for _, day := range days {
+		if day.Date != "" && day.Date <= cutoff {
+			byDate[day.Date] = day
+		}
+	}
+
+	sorted := make([]ContributionDay, 0, len(byDate))
+	for _, day := range byDate {
+		sorted = append(sorted, day)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	stats := ContributionStats{
+		Years:    []YearTotal{},
+		Months:   []MonthTotal{},
+		Weekdays: make([]WeekdayTotal, 7),
+	}
+	for weekday := range stats.Weekdays {
+		stats.Weekdays[weekday] = WeekdayTotal{Weekday: weekday, Name: time.Weekday(weekday).String()}
+	}
+
+	if len(sorted) == 0 {
+		return stats
+	}
+
+	weekdayDays := make([]int, 7)
+	current := Streak{}
+	var previous time.Time
+
+	for _, day := range sorted {
+		date, err := time.Parse(dateLayout, day.Date)
+		if err != nil {
+			continue
+		}
+		count := day.ContributionCount
+
+		stats.TotalContributions += count
+
+		if year := date.Year(); len(stats.Years) == 0 || stats.Years[len(stats.Years)-1].Year != year {
+			stats.Years = append(stats.Years, YearTotal{Year: year})
+		}
+		stats.Years[len(stats.Years)-1].Total += count
+
+		if month := day.Date[:7]; len(stats.Months) == 0 || stats.Months[len(stats.Months)-1].Month != month {
+			stats.Months = append(stats.Months, MonthTotal{Month: month})
+		}
+		stats.Months[len(stats.Months)-1].Total += count
+
+		weekday := int(date.Weekday())
+		stats.Weekdays[weekday].Total += count
+		weekdayDays[weekday]++
+
+		if count > 0 {
+			if stats.FirstContribution == "" {
+				stats.FirstContribution = day.Date
+			}
+
+			if stats.BusiestDay == nil || count > stats.BusiestDay.ContributionCount {
+				stats.BusiestDay = &BusiestDay{Date: day.Date, ContributionCount: count}
+			}
+
+			// A gap in the data (e.g. between fetched years) breaks the streak
+			if current.Length > 0 && date.Sub(previous) == 24*time.Hour {
+				current.Length++
+				current.End = day.Date
+			} else {
+				current = Streak{Length: 1, Start: day.Date, End: day.Date}
+			}
+
+			if current.Length > stats.LongestStreak.Length {
+				stats.LongestStreak = current
+			}
+		} else {
+			current = Streak{}
+		}
+
+		previous = date
+	}
+
+	for weekday, total := range weekdayDays {
+		if total > 0 {
+			stats.Weekdays[weekday].Average = round(float64(stats.Weekdays[weekday].Total) / float64(total))
+		}
+	}
+
+	// Today's count may still be 0 without the streak being over, so a streak ending yesterday is current
+	last := sorted[len(sorted)-1]
+	if current.Length > 0 {
+		stats.CurrentStreak = current
+	} else if last.ContributionCount == 0 && len(sorted) > 1 {
+		stats.CurrentStreak = trailingStreak(sorted[:len(sorted)-1])
+	}
+
+	lastDate, _ := time.Parse(dateLayout, last.Date)
+	windowStart := lastDate.AddDate(0, 0, -29).Format(dateLayout)
+	windowTotal := 0
+	for i := len(sorted) - 1; i >= 0 && sorted[i].Date >= windowStart; i-- {
+		windowTotal += sorted[i].ContributionCount
+	}
+	stats.RollingAverage = round(float64(windowTotal) / 30)
+
+	return stats
+}
+
+// trailingStreak returns the run of consecutive active days ending at the last day
+func trailingStreak(days []ContributionDay) Streak {
+	streak := Streak{}
+	var next time.Time
+
+	for i := len(days) - 1; i >= 0; i-- {
+		date, err := time.Parse(dateLayout, days[i].Date)
+		if err != nil || days[i].ContributionCount == 0 || (streak.Length > 0 && next.Sub(date) != 24*time.Hour) {
+			break
+		}
+
+		if streak.Length == 0 {
+			streak.End = days[i].Date
+		}
+		streak.Length++
+		streak.Start = days[i].Date
+		next = date
+	}
+
+	return streak
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,226 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// daysFrom builds consecutive contribution days starting at start, one per count
+func daysFrom(start string, counts ...int) []ContributionDay {
+	date, err := time.Parse(dateLayout, start)
+	if err != nil {
+		panic(err)
+	}
+
+	days := make([]ContributionDay, 0, len(counts))
+	for i, count := range counts {
+		day := date.AddDate(0, 0, i)
+		days = append(days, ContributionDay{Date: day.Format(dateLayout), ContributionCount: count, Weekday: int(day.Weekday())})
+	}
+	return days
+}
+
+func concatDays(parts ...[]ContributionDay) []ContributionDay {
+	var days []ContributionDay
+	for _, part := range parts {
+		days = append(days, part...)
+	}
+	return days
+}
+
+func TestComputeStats(t *testing.T) {
+	// Noon UTC, so the +14h cutoff is the next day
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		days          []ContributionDay
+		now           time.Time
+		wantTotal     int
+		wantFirst     string
+		wantCurrent   Streak
+		wantLongest   Streak
+		wantYears     []YearTotal
+		wantBusiest   *BusiestDay
+		wantLastMonth MonthTotal
+	}{
+		{
+			name:          "streak running through today",
+			days:          daysFrom("2024-03-06", 0, 2, 1, 3, 1),
+			now:           now,
+			wantTotal:     7,
+			wantFirst:     "2024-03-07",
+			wantCurrent:   Streak{Length: 4, Start: "2024-03-07", End: "2024-03-10"},
+			wantLongest:   Streak{Length: 4, Start: "2024-03-07", End: "2024-03-10"},
+			wantYears:     []YearTotal{{Year: 2024, Total: 7}},
+			wantBusiest:   &BusiestDay{Date: "2024-03-09", ContributionCount: 3},
+			wantLastMonth: MonthTotal{Month: "2024-03", Total: 7},
+		},
+		{
+			name:          "streak ending yesterday is still current",
+			days:          daysFrom("2024-03-06", 1, 0, 2, 5, 0),
+			now:           now,
+			wantTotal:     8,
+			wantFirst:     "2024-03-06",
+			wantCurrent:   Streak{Length: 2, Start: "2024-03-08", End: "2024-03-09"},
+			wantLongest:   Streak{Length: 2, Start: "2024-03-08", End: "2024-03-09"},
+			wantYears:     []YearTotal{{Year: 2024, Total: 8}},
+			wantBusiest:   &BusiestDay{Date: "2024-03-09", ContributionCount: 5},
+			wantLastMonth: MonthTotal{Month: "2024-03", Total: 8},
+		},
+		{
+			name:          "streak ending before yesterday is over",
+			days:          daysFrom("2024-03-05", 1, 1, 1, 0, 0, 0),
+			now:           now,
+			wantTotal:     3,
+			wantFirst:     "2024-03-05",
+			wantLongest:   Streak{Length: 3, Start: "2024-03-05", End: "2024-03-07"},
+			wantYears:     []YearTotal{{Year: 2024, Total: 3}},
+			wantBusiest:   &BusiestDay{Date: "2024-03-05", ContributionCount: 1},
+			wantLastMonth: MonthTotal{Month: "2024-03", Total: 3},
+		},
+		{
+			name: "duplicate days from overlapping calendars count once",
+			days: concatDays(
+				daysFrom("2024-03-07", 1, 2, 3),
+				daysFrom("2024-03-08", 2, 3, 4),
+			),
+			now:           now,
+			wantTotal:     10,
+			wantFirst:     "2024-03-07",
+			wantCurrent:   Streak{Length: 4, Start: "2024-03-07", End: "2024-03-10"},
+			wantLongest:   Streak{Length: 4, Start: "2024-03-07", End: "2024-03-10"},
+			wantYears:     []YearTotal{{Year: 2024, Total: 10}},
+			wantBusiest:   &BusiestDay{Date: "2024-03-10", ContributionCount: 4},
+			wantLastMonth: MonthTotal{Month: "2024-03", Total: 10},
+		},
+		{
+			name:          "days up to 14 hours ahead of UTC are kept",
+			days:          daysFrom("2024-03-10", 1, 2, 4),
+			now:           now,
+			wantTotal:     3,
+			wantFirst:     "2024-03-10",
+			wantCurrent:   Streak{Length: 2, Start: "2024-03-10", End: "2024-03-11"},
+			wantLongest:   Streak{Length: 2, Start: "2024-03-10", End: "2024-03-11"},
+			wantYears:     []YearTotal{{Year: 2024, Total: 3}},
+			wantBusiest:   &BusiestDay{Date: "2024-03-11", ContributionCount: 2},
+			wantLastMonth: MonthTotal{Month: "2024-03", Total: 3},
+		},
+		{
+			name:          "tomorrow is dropped before 10:00 UTC",
+			days:          daysFrom("2024-03-09", 1, 2, 4),
+			now:           time.Date(2024, 3, 10, 9, 59, 0, 0, time.UTC),
+			wantTotal:     3,
+			wantFirst:     "2024-03-09",
+			wantCurrent:   Streak{Length: 2, Start: "2024-03-09", End: "2024-03-10"},
+			wantLongest:   Streak{Length: 2, Start: "2024-03-09", End: "2024-03-10"},
+			wantYears:     []YearTotal{{Year: 2024, Total: 3}},
+			wantBusiest:   &BusiestDay{Date: "2024-03-10", ContributionCount: 2},
+			wantLastMonth: MonthTotal{Month: "2024-03", Total: 3},
+		},
+		{
+			name: "streak runs across the new year",
+			days: concatDays(
+				daysFrom("2023-12-30", 1, 1),
+				daysFrom("2024-01-01", 1, 0),
+			),
+			now:           time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+			wantTotal:     3,
+			wantFirst:     "2023-12-30",
+			wantCurrent:   Streak{Length: 3, Start: "2023-12-30", End: "2024-01-01"},
+			wantLongest:   Streak{Length: 3, Start: "2023-12-30", End: "2024-01-01"},
+			wantYears:     []YearTotal{{Year: 2023, Total: 2}, {Year: 2024, Total: 1}},
+			wantBusiest:   &BusiestDay{Date: "2023-12-30", ContributionCount: 1},
+			wantLastMonth: MonthTotal{Month: "2024-01", Total: 1},
+		},
+		{
+			name: "a missing year between calendars breaks the streak",
+			days: concatDays(
+				daysFrom("2022-12-29", 1, 1, 1),
+				daysFrom("2024-01-01", 2, 2),
+			),
+			now:           time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+			wantTotal:     7,
+			wantFirst:     "2022-12-29",
+			wantCurrent:   Streak{Length: 2, Start: "2024-01-01", End: "2024-01-02"},
+			wantLongest:   Streak{Length: 3, Start: "2022-12-29", End: "2022-12-31"},
+			wantYears:     []YearTotal{{Year: 2022, Total: 3}, {Year: 2024, Total: 4}},
+			wantBusiest:   &BusiestDay{Date: "2024-01-01", ContributionCount: 2},
+			wantLastMonth: MonthTotal{Month: "2024-01", Total: 4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stats := ComputeStats(test.days, test.now)
+
+			if stats.TotalContributions != test.wantTotal {
+				t.Errorf("TotalContributions = %d, want %d", stats.TotalContributions, test.wantTotal)
+			}
+			if stats.FirstContribution != test.wantFirst {
+				t.Errorf("FirstContribution = %q, want %q", stats.FirstContribution, test.wantFirst)
+			}
+			if stats.CurrentStreak != test.wantCurrent {
+				t.Errorf("CurrentStreak = %+v, want %+v", stats.CurrentStreak, test.wantCurrent)
+			}
+			if stats.LongestStreak != test.wantLongest {
+				t.Errorf("LongestStreak = %+v, want %+v", stats.LongestStreak, test.wantLongest)
+			}
+			if !reflect.DeepEqual(stats.Years, test.wantYears) {
+				t.Errorf("Years = %+v, want %+v", stats.Years, test.wantYears)
+			}
+			if !reflect.DeepEqual(stats.BusiestDay, test.wantBusiest) {
+				t.Errorf("BusiestDay = %+v, want %+v", stats.BusiestDay, test.wantBusiest)
+			}
+			if len(stats.Months) == 0 || stats.Months[len(stats.Months)-1] != test.wantLastMonth {
+				t.Errorf("Months = %+v, want the last to be %+v", stats.Months, test.wantLastMonth)
+			}
+
+			weekdayTotal := 0
+			for _, weekday := range stats.Weekdays {
+				weekdayTotal += weekday.Total
+			}
+			if weekdayTotal != test.wantTotal {
+				t.Errorf("weekday totals add up to %d, want %d", weekdayTotal, test.wantTotal)
+			}
+		})
+	}
+}
+
+func TestComputeStatsAverages(t *testing.T) {
+	// Four weeks of 1 contribution a day, plus 2 extra on each Monday
+	counts := make([]int, 28)
+	for i := range counts {
+		counts[i] = 1
+	}
+	days := daysFrom("2024-02-12", counts...) // a Monday
+	for i := 0; i < len(days); i += 7 {
+		days[i].ContributionCount = 3
+	}
+
+	stats := ComputeStats(days, time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC))
+
+	if got := stats.Weekdays[time.Monday]; got.Total != 12 || got.Average != 3 || got.Name != "Monday" {
+		t.Errorf("Monday = %+v, want total 12, average 3", got)
+	}
+	if got := stats.Weekdays[time.Sunday]; got.Total != 4 || got.Average != 1 {
+		t.Errorf("Sunday = %+v, want total 4, average 1", got)
+	}
+
+	// 36 contributions over the 30 day window
+	if stats.RollingAverage != 1.2 {
+		t.Errorf("RollingAverage = %v, want 1.2", stats.RollingAverage)
+	}
+}
+
+func TestComputeStatsEmpty(t *testing.T) {
+	stats := ComputeStats(nil, time.Now())
+
+	if stats.TotalContributions != 0 || stats.BusiestDay != nil || stats.CurrentStreak != (Streak{}) {
+		t.Errorf("stats = %+v, want zero values", stats)
+	}
+	if stats.Years == nil || stats.Months == nil || len(stats.Weekdays) != 7 {
+		t.Errorf("stats = %+v, want empty (non-nil) slices and all 7 weekdays", stats)
+	}
+}
@@ -0,0 +1,39 @@
+package github
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var ErrInvalidRepositoryURL = errors.New("not a GitHub repository URL")
+
+// ParseRepositoryURL extracts the owner and repository name from a GitHub URL, accepting
+// https://github.com/owner/repo (with or without .git or a trailing /tree/... path),
+// github.com/owner/repo and git@github.com:owner/repo.git
+func ParseRepositoryURL(rawURL string) (owner string, repo string, err error) {
+	rawURL = strings.TrimSpace(rawURL)
+
+	if rest, ok := strings.CutPrefix(rawURL, "git@github.com:"); ok {
+		rawURL = "https://github.com/" + rest
+	} else if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", ErrInvalidRepositoryURL
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	if host != "github.com" {
+		return "", "", ErrInvalidRepositoryURL
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", ErrInvalidRepositoryURL
+	}
+
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), nil
+}
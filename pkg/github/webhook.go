@@ -0,0 +1,25 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// VerifyWebhookSignature checks the X-Hub-Signature-256 header ("sha256=<hex HMAC of the body>")
+func VerifyWebhookSignature(secret []byte, body []byte, signature string) bool {
+	signatureHex, found := strings.CutPrefix(signature, "sha256=")
+	if !found || len(secret) == 0 {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signatureHex)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,47 @@
+package initializers
+
+import (
+	"os"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/cache"
+	log "github.com/sirupsen/logrus"
+)
+
+var CacheStore cache.Store
+
+// GitHubCache caches GitHub API responses for GITHUB_CACHE_TTL, serving them stale for up to
+// GITHUB_CACHE_STALE_TTL while they are refreshed (or while GitHub is unavailable)
+var GitHubCache *cache.Cache
+
+// InitializeCache must run after InitializeDB
+func InitializeCache() {
+	memory := cache.NewMemoryStore()
+
+	switch store := os.Getenv("CACHE_STORE"); store {
+	case "", "memory":
+		CacheStore = memory
+	case "database":
+		database := cache.NewDatabaseStore(DB)
+		if err := database.DeleteExpired(); err != nil {
+			log.Warn("Error deleting expired cache entries: ", err)
+		}
+		CacheStore = cache.NewTieredStore(memory, database)
+	case "redis":
+		CacheStore = cache.NewTieredStore(memory, cache.NewRedisStore(getRedisClient(), "cache:"))
+	default:
+		log.Fatalf("Unknown CACHE_STORE %q", store)
+	}
+
+	GitHubCache = cache.New(CacheStore, getEnvDuration("GITHUB_CACHE_TTL", 15*time.Minute), getEnvDuration("GITHUB_CACHE_STALE_TTL", 24*time.Hour))
+
+	log.Info("Cache initialized")
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,50 @@
+package initializers
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/captcha"
+	log "github.com/sirupsen/logrus"
+)
+
+var Captcha captcha.CaptchaVerifier
+
+func InitializeCaptcha() {
+	config := captcha.Config{
+		SecretKey:      os.Getenv("CAPTCHA_SECRET_KEY"),
+		VerifyURL:      os.Getenv("CAPTCHA_VERIFY_URL"),
+		MinScore:       0.5,
+		ExpectedAction: os.Getenv("CAPTCHA_EXPECTED_ACTION"),
+	}
+
+	// Older deployments only set the reCAPTCHA key
+	if config.SecretKey == "" {
+		config.SecretKey = os.Getenv("RECAPTCHA_SECRET_KEY")
+	}
+
+	if minScore := os.Getenv("CAPTCHA_MIN_SCORE"); minScore != "" {
+		score, err := strconv.ParseFloat(minScore, 64)
+		if err != nil || score < 0 || score > 1 {
+			log.Fatalf("Invalid CAPTCHA_MIN_SCORE %q, expected a number between 0 and 1", minScore)
+		}
+		config.MinScore = score
+	}
+
+	config.AllowedHostnames = splitEnvList("CAPTCHA_ALLOWED_HOSTNAMES")
+
+	switch provider := os.Getenv("CAPTCHA_PROVIDER"); provider {
+	case "", "recaptcha_v3":
+		Captcha = captcha.NewRecaptchaV3(config)
+	case "recaptcha_v2":
+		Captcha = captcha.NewRecaptchaV2(config)
+	case "hcaptcha":
+		Captcha = captcha.NewHCaptcha(config)
+	case "turnstile":
+		Captcha = captcha.NewTurnstile(config)
+	default:
+		log.Fatalf("Unknown CAPTCHA_PROVIDER %q", provider)
+	}
+
+	log.Info("Captcha initialized")
+}
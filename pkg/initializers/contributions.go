@@ -0,0 +1,37 @@
+package initializers
+
+import (
+	"os"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/contributions"
+	log "github.com/sirupsen/logrus"
+)
+
+// ContributionAccounts are the GitLab and Gitea / Forgejo accounts merged with the default GitHub user by GET /contributions
+var ContributionAccounts []contributions.Account
+
+func InitializeContributionProviders() {
+	timeout := getEnvDuration("CONTRIBUTIONS_TIMEOUT", 15*time.Second)
+
+	if user := os.Getenv("GITLAB_USER"); user != "" {
+		ContributionAccounts = append(ContributionAccounts, contributions.Account{
+			Provider: contributions.NewGitLabProvider(os.Getenv("GITLAB_URL"), os.Getenv("GITLAB_TOKEN"), timeout),
+			Login:    user,
+		})
+	}
+
+	if user := os.Getenv("GITEA_USER"); user != "" {
+		giteaURL := os.Getenv("GITEA_URL")
+		if giteaURL == "" {
+			log.Fatal("GITEA_URL is required when GITEA_USER is set")
+		}
+
+		ContributionAccounts = append(ContributionAccounts, contributions.Account{
+			Provider: contributions.NewGiteaProvider(giteaURL, os.Getenv("GITEA_TOKEN"), timeout),
+			Login:    user,
+		})
+	}
+
+	log.Info("Contribution providers initialized (", len(ContributionAccounts), " besides GitHub)")
+}
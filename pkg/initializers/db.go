@@ -28,6 +28,18 @@ func InitializeDB() {
 		&structs.ProjectTechnologies{},
 		&structs.ProjectImages{},
 		&structs.ProjectURLs{},
+		&structs.ProjectRepositories{},
+		&structs.GitHubWebhookDeliveries{},
+		&structs.MediaAssets{},
+		&structs.ContactMessages{},
+		&structs.ContactReplies{},
+		&structs.OutboundEmails{},
+		&structs.OutboundEmailAttempts{},
+		&structs.OutboundEmailAttachments{},
+		&structs.NotificationChannels{},
+		&structs.NotificationDeliveries{},
+		&structs.Settings{},
+		&structs.CacheEntries{},
 	)
 
 	if err != nil {
@@ -0,0 +1,31 @@
+package initializers
+
+import (
+	"os"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+	log "github.com/sirupsen/logrus"
+)
+
+var GitHub *github.Client
+
+// GitHubWebhookSecret verifies POST /webhooks/github deliveries, webhooks are disabled when it is empty
+var GitHubWebhookSecret []byte
+
+func InitializeGitHub() {
+	token := os.Getenv("GITHUB_ACCESS_TOKEN")
+	if token == "" {
+		log.Warn("GITHUB_ACCESS_TOKEN is not set, GitHub requests will fail")
+	}
+
+	GitHub = github.NewClient(github.Config{
+		BaseURL: os.Getenv("GITHUB_API_URL"),
+		Token:   token,
+		Timeout: getEnvDuration("GITHUB_TIMEOUT", 15*time.Second),
+	})
+
+	GitHubWebhookSecret = []byte(os.Getenv("GITHUB_WEBHOOK_SECRET"))
+
+	log.Info("GitHub client initialized")
+}
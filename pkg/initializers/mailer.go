@@ -0,0 +1,51 @@
+package initializers
+
+import (
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/mailer"
+	log "github.com/sirupsen/logrus"
+)
+
+var Mailer mailer.Mailer
+
+func InitializeMailer() {
+	var err error
+
+	fromAddress := os.Getenv("EMAIL_FROM_ADDRESS")
+	if fromAddress == "" {
+		fromAddress = os.Getenv("EMAIL_USERNAME")
+	}
+	from := &mail.Address{Name: os.Getenv("EMAIL_FROM_PREFIX"), Address: fromAddress}
+
+	switch driver := os.Getenv("EMAIL_DRIVER"); driver {
+	case "", "smtp":
+		poolSize, _ := strconv.Atoi(os.Getenv("EMAIL_POOL_SIZE"))
+		Mailer, err = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:          os.Getenv("EMAIL_HOST"),
+			Port:          os.Getenv("EMAIL_PORT"),
+			Username:      os.Getenv("EMAIL_USERNAME"),
+			Password:      os.Getenv("EMAIL_PASSWORD"),
+			Security:      strings.ToLower(strings.TrimSpace(os.Getenv("EMAIL_SECURITY"))),
+			SkipTLSVerify: os.Getenv("EMAIL_TLS_SKIP_VERIFY") == "true",
+			From:          from,
+			PoolSize:      poolSize,
+		})
+	case "http":
+		Mailer, err = mailer.NewHTTPMailer(os.Getenv("EMAIL_API_URL"), os.Getenv("EMAIL_API_KEY"), from)
+	case "dev":
+		Mailer, err = mailer.NewDevMailer(getEnvDefault("EMAIL_DEV_DIR", "./mail"), from)
+		log.Warn("EMAIL_DRIVER is dev, emails are captured to disk instead of being sent")
+	default:
+		log.Fatalf("Unknown EMAIL_DRIVER %q", driver)
+	}
+
+	if err != nil {
+		log.Fatal("Error initializing mailer: ", err)
+	}
+
+	log.Info("Mailer initialized")
+}
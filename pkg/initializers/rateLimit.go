@@ -0,0 +1,83 @@
+package initializers
+
+import (
+	"os"
+	"strings"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+var RateLimiter ratelimit.Store
+
+// RateLimitPolicies holds the enabled policies by name, see defaultRateLimits
+var RateLimitPolicies = map[string]ratelimit.Policy{}
+
+// defaultRateLimits can be overridden with RATE_LIMIT_<NAME> (e.g. RATE_LIMIT_CONTACT="5/1h,burst=2"), or "off"
+var defaultRateLimits = map[string]string{
+	"contact":  "5/1h",
+	"login":    "10/15m",
+	"register": "5/1h",
+	"github":   "30/1m",
+}
+
+func InitializeRateLimiter() {
+	switch store := os.Getenv("RATE_LIMIT_STORE"); store {
+	case "", "memory":
+		RateLimiter = ratelimit.NewMemoryStore()
+	case "redis":
+		RateLimiter = ratelimit.NewRedisStore(getRedisClient(), "ratelimit:")
+	default:
+		log.Fatalf("Unknown RATE_LIMIT_STORE %q", store)
+	}
+
+	for name, fallback := range defaultRateLimits {
+		value := getEnvDefault("RATE_LIMIT_"+strings.ToUpper(name), fallback)
+		if value == "off" {
+			log.Warnf("Rate limit %q is disabled", name)
+			continue
+		}
+
+		policy, err := ratelimit.ParsePolicy(name, value, ratelimit.KEY_BY_IP)
+		if err != nil {
+			log.Fatal("Error parsing RATE_LIMIT_", strings.ToUpper(name), ": ", err)
+		}
+		RateLimitPolicies[name] = policy
+	}
+
+	log.Info("Rate limiter initialized")
+}
+
+// trustedPlatforms maps TRUSTED_PLATFORM onto the headers gin trusts for the client IP
+var trustedPlatforms = map[string]string{
+	"cloudflare": gin.PlatformCloudflare,
+	"google":     gin.PlatformGoogleAppEngine,
+	"flyio":      gin.PlatformFlyIO,
+}
+
+// ConfigureTrustedProxies makes c.ClientIP() only read X-Forwarded-For when the request came
+// through one of TRUSTED_PROXIES (IPs or CIDRs). Unset is the same as "none", trusting no proxy.
+func ConfigureTrustedProxies(router *gin.Engine) {
+	var proxies []string
+	switch strings.TrimSpace(os.Getenv("TRUSTED_PROXIES")) {
+	case "":
+		log.Warn(`TRUSTED_PROXIES is not set, trusting no proxy. Behind a reverse proxy every visitor shares the proxy's rate limits, list it in TRUSTED_PROXIES (or set "none" to silence this)`)
+	case "none":
+	default:
+		proxies = splitEnvList("TRUSTED_PROXIES")
+	}
+
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		log.Fatal("Error parsing TRUSTED_PROXIES: ", err)
+	}
+
+	// Platforms such as Cloudflare set a single header with the client IP
+	if platform := os.Getenv("TRUSTED_PLATFORM"); platform != "" {
+		header, ok := trustedPlatforms[strings.ToLower(platform)]
+		if !ok {
+			log.Fatalf("Unknown TRUSTED_PLATFORM %q, expected cloudflare, google or flyio", platform)
+		}
+		router.TrustedPlatform = header
+	}
+}
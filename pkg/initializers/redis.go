@@ -0,0 +1,43 @@
+package initializers
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+)
+
+// Redis is only connected when a feature is configured to use it (RATE_LIMIT_STORE, CACHE_STORE).
+// Works with Redis, Valkey, KeyDB and Dragonfly.
+var Redis *redis.Client
+
+func getRedisClient() *redis.Client {
+	if Redis != nil {
+		return Redis
+	}
+
+	// e.g. redis://:password@localhost:6379/0, or rediss:// for TLS
+	options, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+	if err != nil {
+		log.Fatal("Error parsing REDIS_URL: ", err)
+	}
+
+	if poolSize, err := strconv.Atoi(getEnvDefault("REDIS_POOL_SIZE", "10")); err == nil && poolSize > 0 {
+		options.PoolSize = poolSize
+	}
+
+	client := redis.NewClient(options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Fatal("Error connecting to Redis: ", err)
+	}
+
+	Redis = client
+	return Redis
+}
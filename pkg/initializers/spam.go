@@ -0,0 +1,130 @@
+package initializers
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/ratelimit"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/spam"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	log "github.com/sirupsen/logrus"
+)
+
+var SpamFilter *spam.Pipeline
+
+// FormTokenSecret signs the tokens handed out by GET /contact/token
+var FormTokenSecret []byte
+
+// InitializeSpamFilter builds the contact form spam pipeline, must run after InitializeDB
+func InitializeSpamFilter() {
+	FormTokenSecret = signingKeyFromEnv("CONTACT_FORM_SECRET")
+
+	keywords, patterns := loadSpamBlocklist()
+
+	SpamFilter = spam.NewPipeline(getEnvFloat("SPAM_THRESHOLD", 1),
+		&spam.HoneypotFilter{},
+		&spam.FormTokenFilter{
+			Secret:       FormTokenSecret,
+			MinAge:       time.Duration(getEnvFloat("SPAM_MIN_SUBMIT_SECONDS", 3) * float64(time.Second)),
+			MaxAge:       24 * time.Hour,
+			MissingScore: getEnvFloat("SPAM_MISSING_FORM_TOKEN_SCORE", 1),
+			Claim:        claimFormToken,
+		},
+		&spam.LinkFilter{MaxLinks: int(getEnvFloat("SPAM_MAX_LINKS", 2)), ScorePerLink: 0.25},
+		&spam.BlocklistFilter{Keywords: keywords, Patterns: patterns, ScorePerHit: 0.5},
+		&spam.DisposableEmailFilter{Domains: spam.DisposableDomains(splitEnvList("SPAM_DISPOSABLE_DOMAINS")...), Score: 0.6},
+		&spam.DuplicateFilter{
+			Window: 24 * time.Hour,
+			Score:  0.6,
+			CountRecent: func(messageHash string, since time.Time) (int64, error) {
+				var count int64
+				err := DB.Model(&structs.ContactMessages{}).Where("message_hash = ? AND created_at >= ?", messageHash, since).Count(&count).Error
+				return count, err
+			},
+		},
+	)
+
+	log.Info("Spam filter initialized")
+}
+
+// claimFormToken uses a single token bucket per nonce in the rate limit store, so tokens are single-use
+// across replicas when it is Redis. It must not be called before InitializeRateLimiter.
+func claimFormToken(nonce string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return true, nil
+	}
+
+	result, err := RateLimiter.Take(nonce, ratelimit.Policy{Name: "formtoken", Limit: 1, Period: ttl, Burst: 1})
+	return result.Allowed, err
+}
+
+// loadSpamBlocklist reads SPAM_KEYWORDS and SPAM_BLOCKLIST_FILE, where each line of the file is
+// a keyword, or a regular expression when wrapped in slashes (e.g. /crypto\s+invest/i)
+func loadSpamBlocklist() ([]string, []*regexp.Regexp) {
+	keywords := splitEnvList("SPAM_KEYWORDS")
+	var patterns []*regexp.Regexp
+
+	path := os.Getenv("SPAM_BLOCKLIST_FILE")
+	if path == "" {
+		return keywords, patterns
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal("Error opening SPAM_BLOCKLIST_FILE: ", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") && strings.Count(line, "/") >= 2 {
+			end := strings.LastIndex(line, "/")
+			expression := line[1:end]
+			if strings.Contains(line[end+1:], "i") {
+				expression = "(?i)" + expression
+			}
+
+			pattern, err := regexp.Compile(expression)
+			if err != nil {
+				log.Fatalf("Invalid pattern %q in SPAM_BLOCKLIST_FILE: %v", line, err)
+			}
+			patterns = append(patterns, pattern)
+			continue
+		}
+
+		keywords = append(keywords, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatal("Error reading SPAM_BLOCKLIST_FILE: ", err)
+	}
+
+	return keywords, patterns
+}
+
+func splitEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
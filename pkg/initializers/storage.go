@@ -0,0 +1,124 @@
+package initializers
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/storage"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	log "github.com/sirupsen/logrus"
+)
+
+var Storage storage.Storage
+
+func InitializeStorage() {
+	var err error
+
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "s3":
+		Storage, err = storage.NewS3Storage(storage.S3Config{
+			Endpoint:  os.Getenv("BUCKET_ENDPOINT_IP") + ":" + os.Getenv("BUCKET_ENDPOINT_PORT"),
+			UseSSL:    os.Getenv("BUCKET_ENDPOINT_USE_SSL") == "true",
+			Region:    os.Getenv("BUCKET_REGION"),
+			Bucket:    os.Getenv("BUCKET_NAME"),
+			AccessKey: os.Getenv("BUCKET_ACCESS_KEY"),
+			SecretKey: os.Getenv("BUCKET_SECRET_KEY"),
+
+			PublicBaseURL:        os.Getenv("STORAGE_PUBLIC_URL"),
+			ServerSideEncryption: serverSideEncryptionFromEnv(),
+		})
+	case "local":
+		Storage, err = storage.NewLocalStorage(storage.LocalConfig{
+			Root:          getEnvDefault("STORAGE_LOCAL_PATH", "./storage"),
+			BaseURL:       getEnvDefault("STORAGE_LOCAL_BASE_URL", "http://localhost:"+os.Getenv("REST_PORT")),
+			Secret:        signingKeyFromEnv("STORAGE_SIGNING_KEY"),
+			DownloadTTL:   24 * time.Hour,
+			PublicBaseURL: os.Getenv("STORAGE_PUBLIC_URL"),
+		})
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q", driver)
+	}
+
+	if err != nil {
+		log.Fatal("Error initializing storage: ", err)
+	}
+
+	// Stored image references are object keys, public URLs are built when they are loaded
+	structs.PublicURL = Storage.PublicURL
+
+	log.Info("Storage initialized")
+}
+
+// MigrateStorageKeys converts image references stored as full URLs (before they were
+// stored as object keys) into keys, must run after InitializeDB and InitializeStorage
+func MigrateStorageKeys() {
+	migrateLegacyURLColumn(&structs.ProjectImages{}, "project_images", "image_url", "image_key")
+	migrateLegacyURLColumn(&structs.Technologies{}, "technologies", "technology_image", "technology_image_key")
+}
+
+func migrateLegacyURLColumn(model interface{}, table string, urlColumn string, keyColumn string) {
+	if !DB.Migrator().HasColumn(model, urlColumn) {
+		return
+	}
+
+	var rows []struct {
+		ID  uint
+		URL string
+	}
+	query := fmt.Sprintf("SELECT id, %s AS url FROM %s WHERE (%s IS NULL OR %s = '') AND %s <> ''", urlColumn, table, keyColumn, keyColumn, urlColumn)
+	if err := DB.Raw(query).Scan(&rows).Error; err != nil {
+		log.Error("Error reading legacy storage URLs from ", table, ": ", err)
+		return
+	}
+
+	for _, row := range rows {
+		key, err := Storage.KeyFromURL(row.URL)
+		if err != nil {
+			log.Warnf("Could not convert %s %d URL %q to a storage key: %v", table, row.ID, row.URL, err)
+			continue
+		}
+
+		if err := DB.Table(table).Where("id = ?", row.ID).Update(keyColumn, key).Error; err != nil {
+			log.Error("Error saving storage key for ", table, ": ", err)
+		}
+	}
+
+	if len(rows) > 0 {
+		log.Infof("Migrated %d legacy storage URLs in %s", len(rows), table)
+	}
+}
+
+// signingKeyFromEnv returns the key in the environment variable, or a random key if it isn't set
+func signingKeyFromEnv(envKey string) []byte {
+	if key := os.Getenv(envKey); key != "" {
+		return []byte(key)
+	}
+
+	log.Warn(envKey, " is not set, anything signed with it will not survive a restart")
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatal("Error generating ", envKey, ": ", err)
+	}
+	return key
+}
+
+func getEnvDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// serverSideEncryptionFromEnv reads BUCKET_SERVER_SIDE_ENCRYPTION, uploads are encrypted with AES256 unless it is "none"
+func serverSideEncryptionFromEnv() string {
+	sse := getEnvDefault("BUCKET_SERVER_SIDE_ENCRYPTION", "AES256")
+	if strings.EqualFold(sse, "none") {
+		log.Warn("BUCKET_SERVER_SIDE_ENCRYPTION is none, uploads are not encrypted at rest")
+		return ""
+	}
+	return sse
+}
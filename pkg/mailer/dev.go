@@ -0,0 +1,143 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var ErrMessageNotFound = errors.New("captured message not found")
+
+var unsafeFileCharacters = regexp.MustCompile(`[^a-zA-Z0-9@._-]+`)
+
+// DevMailer captures messages as .eml files instead of sending them, for development and tests
+type DevMailer struct {
+	dir     string
+	from    *mail.Address
+	counter uint64
+}
+
+type CapturedMessage struct {
+	ID      string    `json:"id"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	ReplyTo string    `json:"replyTo"`
+	Subject string    `json:"subject"`
+	Date    time.Time `json:"date"`
+	Size    int64     `json:"size"`
+}
+
+func NewDevMailer(dir string, from *mail.Address) (*DevMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %v", err)
+	}
+
+	return &DevMailer{dir: dir, from: from}, nil
+}
+
+func (m *DevMailer) Send(message Message) error {
+	msg, err := BuildMIME(m.from, message)
+	if err != nil {
+		return fmt.Errorf("failed to build email message: %v", err)
+	}
+
+	// IDs sort by capture time, the counter keeps them unique within the same nanosecond
+	id := strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + strconv.FormatUint(atomic.AddUint64(&m.counter, 1), 10) +
+		"-" + unsafeFileCharacters.ReplaceAllString(message.To, "_")
+
+	if err := os.WriteFile(filepath.Join(m.dir, id+".eml"), msg, 0o644); err != nil {
+		return fmt.Errorf("failed to write captured message: %v", err)
+	}
+
+	return nil
+}
+
+// List returns the captured messages, newest first
+func (m *DevMailer) List() ([]CapturedMessage, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]CapturedMessage, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".eml" {
+			continue
+		}
+
+		id := strings.TrimSuffix(entry.Name(), ".eml")
+		raw, err := m.Raw(id)
+		if err != nil {
+			continue
+		}
+
+		parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+		if err != nil {
+			continue
+		}
+
+		decoder := new(mime.WordDecoder)
+		subject, err := decoder.DecodeHeader(parsed.Header.Get("Subject"))
+		if err != nil {
+			subject = parsed.Header.Get("Subject")
+		}
+		date, _ := parsed.Header.Date()
+
+		messages = append(messages, CapturedMessage{
+			ID:      id,
+			From:    parsed.Header.Get("From"),
+			To:      parsed.Header.Get("To"),
+			ReplyTo: parsed.Header.Get("Reply-To"),
+			Subject: subject,
+			Date:    date,
+			Size:    int64(len(raw)),
+		})
+	}
+
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID > messages[j].ID })
+
+	return messages, nil
+}
+
+// Raw returns the captured .eml content
+func (m *DevMailer) Raw(id string) ([]byte, error) {
+	if id == "" || id != filepath.Base(id) {
+		return nil, ErrMessageNotFound
+	}
+
+	raw, err := os.ReadFile(filepath.Join(m.dir, id+".eml"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrMessageNotFound
+	}
+
+	return raw, err
+}
+
+// Clear removes every captured message
+func (m *DevMailer) Clear() error {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".eml" {
+			if err := os.Remove(filepath.Join(m.dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
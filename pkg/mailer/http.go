@@ -0,0 +1,96 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"time"
+)
+
+// HTTPMailer sends through a transactional email HTTP API using the common
+// Resend-style JSON payload (POST {from, to, reply_to, subject, html, text, attachments})
+type HTTPMailer struct {
+	url    string
+	apiKey string
+	from   *mail.Address
+	client *http.Client
+}
+
+type httpMailPayload struct {
+	From        string               `json:"from"`
+	To          []string             `json:"to"`
+	ReplyTo     string               `json:"reply_to,omitempty"`
+	Subject     string               `json:"subject"`
+	HTML        string               `json:"html"`
+	Text        string               `json:"text"`
+	Attachments []httpMailAttachment `json:"attachments,omitempty"`
+}
+
+type httpMailAttachment struct {
+	Filename    string `json:"filename"`
+	Content     string `json:"content"` // Base64
+	ContentType string `json:"content_type"`
+}
+
+func NewHTTPMailer(url string, apiKey string, from *mail.Address) (*HTTPMailer, error) {
+	if url == "" {
+		return nil, fmt.Errorf("email API URL is not set")
+	}
+
+	return &HTTPMailer{
+		url:    url,
+		apiKey: apiKey,
+		from:   from,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (m *HTTPMailer) Send(message Message) error {
+	payload := httpMailPayload{
+		From:    m.from.String(),
+		To:      []string{message.To},
+		ReplyTo: message.ReplyTo,
+		Subject: message.Subject,
+		HTML:    message.HTML,
+		Text:    message.Text,
+	}
+
+	for _, attachment := range message.Attachments {
+		payload.Attachments = append(payload.Attachments, httpMailAttachment{
+			Filename:    attachment.Filename,
+			Content:     base64.StdEncoding.EncodeToString(attachment.Data),
+			ContentType: attachmentContentType(attachment),
+		})
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to create request payload: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, m.url, bytes.NewReader(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call email API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("email API returned %s: %s", resp.Status, body)
+	}
+
+	return nil
+}
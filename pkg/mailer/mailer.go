@@ -0,0 +1,25 @@
+package mailer
+
+type Content struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type Message struct {
+	To      string
+	ReplyTo string // Optional, e.g. the visitor's address on contact notifications
+	Content
+	Attachments []Attachment
+}
+
+// Mailer is implemented by every mail transport (SMTP, HTTP API, development sink)
+type Mailer interface {
+	Send(message Message) error
+}
@@ -0,0 +1,150 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// BuildMIME builds an RFC 5322 message: multipart/alternative (text & HTML),
+// wrapped in multipart/mixed when there are attachments
+func BuildMIME(from *mail.Address, message Message) ([]byte, error) {
+	var msg bytes.Buffer
+
+	msg.WriteString("From: " + from.String() + "\r\n")
+	msg.WriteString("To: " + (&mail.Address{Address: message.To}).String() + "\r\n")
+	if message.ReplyTo != "" {
+		msg.WriteString("Reply-To: " + (&mail.Address{Address: message.ReplyTo}).String() + "\r\n")
+	}
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("UTF-8", message.Subject) + "\r\n")
+	msg.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("Message-ID: " + generateMessageID(from.Address) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	alternative, alternativeBoundary, err := buildAlternativeBody(message.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(message.Attachments) == 0 {
+		msg.WriteString("Content-Type: multipart/alternative; boundary=\"" + alternativeBoundary + "\"\r\n\r\n")
+		msg.Write(alternative)
+		return msg.Bytes(), nil
+	}
+
+	var mixed bytes.Buffer
+	mixedWriter := multipart.NewWriter(&mixed)
+
+	alternativePart, err := mixedWriter.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=\"" + alternativeBoundary + "\""},
+	})
+	if err != nil {
+		return nil, err
+	}
+	alternativePart.Write(alternative)
+
+	for _, attachment := range message.Attachments {
+		attachmentPart, err := mixedWriter.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachmentContentType(attachment), map[string]string{"name": attachment.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeBase64Lines(attachmentPart, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixedWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	msg.WriteString("Content-Type: multipart/mixed; boundary=\"" + mixedWriter.Boundary() + "\"\r\n\r\n")
+	msg.Write(mixed.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+func buildAlternativeBody(content Content) ([]byte, string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", content.Text},
+		{"text/html; charset=UTF-8", content.HTML},
+	}
+
+	// The preferred (HTML) part goes last
+	for _, part := range parts {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
+		encoder := quotedprintable.NewWriter(partWriter)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, "", err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body.Bytes(), writer.Boundary(), nil
+}
+
+func attachmentContentType(attachment Attachment) string {
+	if attachment.ContentType == "" {
+		return "application/octet-stream"
+	}
+	return attachment.ContentType
+}
+
+// writeBase64Lines writes base64 wrapped at 76 characters per line (RFC 2045)
+func writeBase64Lines(writer io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := writer.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+
+	_, err := writer.Write([]byte(encoded + "\r\n"))
+	return err
+}
+
+func generateMessageID(fromAddress string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(fromAddress, "@"); at != -1 {
+		domain = fromAddress[at+1:]
+	}
+
+	random := make([]byte, 16)
+	rand.Read(random)
+
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain)
+}
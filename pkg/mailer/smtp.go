@@ -0,0 +1,244 @@
+package mailer
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sync"
+	"time"
+)
+
+// SMTP security modes
+const (
+	SECURITY_TLS      = "tls"      // Implicit TLS (usually port 465)
+	SECURITY_STARTTLS = "starttls" // Upgrade a plain connection (usually port 587)
+	SECURITY_NONE     = "none"     // Plain text, only for local relays
+)
+
+// idle SMTP connections are dropped after this, most servers time them out anyway
+const smtpIdleTimeout = 30 * time.Second
+
+// smtpCommandTimeout bounds each command exchange, so a stalled server can't hang the outbox worker
+const smtpCommandTimeout = 30 * time.Second
+
+type SMTPConfig struct {
+	Host          string
+	Port          string
+	Username      string
+	Password      string
+	Security      string // SECURITY_TLS (default), SECURITY_STARTTLS or SECURITY_NONE
+	SkipTLSVerify bool
+	From          *mail.Address
+	PoolSize      int
+}
+
+type SMTPMailer struct {
+	config SMTPConfig
+
+	poolMutex sync.Mutex
+	pool      []*smtpConnection
+}
+
+type smtpConnection struct {
+	client   *smtp.Client
+	conn     net.Conn
+	lastUsed time.Time
+}
+
+// extendDeadline gives the next command exchange smtpCommandTimeout to complete
+func (c *smtpConnection) extendDeadline() error {
+	return c.conn.SetDeadline(time.Now().Add(smtpCommandTimeout))
+}
+
+func NewSMTPMailer(config SMTPConfig) (*SMTPMailer, error) {
+	switch config.Security {
+	case "":
+		config.Security = SECURITY_TLS
+	case SECURITY_TLS, SECURITY_STARTTLS, SECURITY_NONE:
+	default:
+		return nil, fmt.Errorf("unknown SMTP security mode %q", config.Security)
+	}
+
+	if config.PoolSize <= 0 {
+		config.PoolSize = 2
+	}
+
+	return &SMTPMailer{config: config}, nil
+}
+
+// Send delivers the message over a pooled SMTP connection
+func (m *SMTPMailer) Send(message Message) error {
+	msg, err := BuildMIME(m.config.From, message)
+	if err != nil {
+		return fmt.Errorf("failed to build email message: %v", err)
+	}
+
+	connection, err := m.getConnection()
+	if err != nil {
+		return err
+	}
+
+	if err := deliver(connection, m.config.From.Address, message.To, msg); err != nil {
+		// The connection may be in an unknown state, don't reuse it
+		connection.client.Close()
+		return err
+	}
+
+	m.releaseConnection(connection)
+	return nil
+}
+
+func deliver(connection *smtpConnection, from string, to string, msg []byte) error {
+	client := connection.client
+
+	// Set sender and recipient
+	if err := connection.extendDeadline(); err != nil {
+		return err
+	}
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("failed to set sender email: %v", err)
+	}
+
+	if err := connection.extendDeadline(); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("failed to set recipient email: %v", err)
+	}
+
+	// Send email data
+	if err := connection.extendDeadline(); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email data: %v", err)
+	}
+
+	if err := connection.extendDeadline(); err != nil {
+		return err
+	}
+	if _, err := writer.Write(msg); err != nil {
+		return fmt.Errorf("failed to write email message: %v", err)
+	}
+
+	// Closing waits for the server to accept the message
+	if err := connection.extendDeadline(); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close email writer: %v", err)
+	}
+
+	return nil
+}
+
+// getConnection reuses an idle pooled connection when it is still alive, otherwise dials a new one
+func (m *SMTPMailer) getConnection() (*smtpConnection, error) {
+	for {
+		connection := m.popConnection()
+		if connection == nil {
+			break
+		}
+
+		// The NOOP round trip happens outside the lock so other sends aren't held up by it
+		if time.Since(connection.lastUsed) < smtpIdleTimeout && connection.extendDeadline() == nil && connection.client.Noop() == nil {
+			return connection, nil
+		}
+		connection.client.Close()
+	}
+
+	return m.dial()
+}
+
+// popConnection takes the most recently used idle connection from the pool, or returns nil when it is empty
+func (m *SMTPMailer) popConnection() *smtpConnection {
+	m.poolMutex.Lock()
+	defer m.poolMutex.Unlock()
+
+	if len(m.pool) == 0 {
+		return nil
+	}
+
+	connection := m.pool[len(m.pool)-1]
+	m.pool = m.pool[:len(m.pool)-1]
+	return connection
+}
+
+func (m *SMTPMailer) releaseConnection(connection *smtpConnection) {
+	if err := connection.extendDeadline(); err != nil {
+		connection.client.Close()
+		return
+	}
+	if err := connection.client.Reset(); err != nil {
+		connection.client.Close()
+		return
+	}
+	connection.lastUsed = time.Now()
+
+	m.poolMutex.Lock()
+	defer m.poolMutex.Unlock()
+
+	if len(m.pool) >= m.config.PoolSize {
+		connection.client.Quit()
+		return
+	}
+	m.pool = append(m.pool, connection)
+}
+
+func (m *SMTPMailer) dial() (*smtpConnection, error) {
+	// Certificates are verified unless explicitly disabled
+	tlsconfig := &tls.Config{
+		InsecureSkipVerify: m.config.SkipTLSVerify,
+		ServerName:         m.config.Host,
+	}
+
+	address := net.JoinHostPort(m.config.Host, m.config.Port)
+	dialer := &net.Dialer{Timeout: 15 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if m.config.Security == SECURITY_TLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsconfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server: %v", err)
+	}
+
+	// The greeting, STARTTLS and AUTH exchanges share the first deadline
+	connection := &smtpConnection{conn: conn}
+	if err := connection.extendDeadline(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to set SMTP deadline: %v", err)
+	}
+
+	// Create an SMTP client from the connection
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create SMTP client: %v", err)
+	}
+
+	if m.config.Security == SECURITY_STARTTLS {
+		if err := client.StartTLS(tlsconfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to start TLS: %v", err)
+		}
+	}
+
+	// Authenticate with SMTP server (net/smtp refuses PLAIN auth over unencrypted connections to remote hosts)
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to authenticate with SMTP server: %v", err)
+		}
+	}
+
+	connection.client = client
+	return connection, nil
+}
@@ -0,0 +1,25 @@
+package notify
+
+import "errors"
+
+type DiscordNotifier struct {
+	webhookURL string
+}
+
+func NewDiscordNotifier(webhookURL string) (*DiscordNotifier, error) {
+	if webhookURL == "" {
+		return nil, errors.New("discord webhook URL is required")
+	}
+	return &DiscordNotifier{webhookURL: webhookURL}, nil
+}
+
+func (n *DiscordNotifier) Send(notification Notification) error {
+	// Embed descriptions are limited to 4096 characters and titles to 256
+	return postJSON(n.webhookURL, map[string]interface{}{
+		"embeds": []map[string]interface{}{{
+			"title":       truncate(notification.Title, 256),
+			"description": truncate(notification.Message, 4096),
+		}},
+		"allowed_mentions": map[string]interface{}{"parse": []string{}},
+	}, nil)
+}
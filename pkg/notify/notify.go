@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Notification is rendered once per channel, Data is the raw event payload for webhooks
+type Notification struct {
+	Event   string
+	Title   string
+	Message string
+	Data    json.RawMessage
+}
+
+// Notifier is implemented by every notification channel (Discord, Slack, ntfy, webhook)
+type Notifier interface {
+	Send(notification Notification) error
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+func postJSON(url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return post(url, "application/json", body, headers)
+}
+
+func post(url string, contentType string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(responseBody)))
+	}
+
+	return nil
+}
+
+// truncate shortens s to at most limit runes, for services that reject long messages
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"errors"
+	"mime"
+	"strings"
+)
+
+const defaultNtfyServer = "https://ntfy.sh"
+
+type NtfyNotifier struct {
+	topicURL    string
+	accessToken string
+}
+
+// NewNtfyNotifier publishes to the topic on serverURL (ntfy.sh when empty), the access token is optional
+func NewNtfyNotifier(serverURL string, topic string, accessToken string) (*NtfyNotifier, error) {
+	if topic == "" {
+		return nil, errors.New("ntfy topic is required")
+	}
+
+	if serverURL == "" {
+		serverURL = defaultNtfyServer
+	}
+
+	return &NtfyNotifier{
+		topicURL:    strings.TrimSuffix(serverURL, "/") + "/" + topic,
+		accessToken: accessToken,
+	}, nil
+}
+
+func (n *NtfyNotifier) Send(notification Notification) error {
+	headers := map[string]string{
+		// Headers must be ASCII, ntfy decodes RFC 2047 encoded titles
+		"Title": mime.QEncoding.Encode("utf-8", notification.Title),
+		"Tags":  "email",
+	}
+
+	if n.accessToken != "" {
+		headers["Authorization"] = "Bearer " + n.accessToken
+	}
+
+	return post(n.topicURL, "text/plain; charset=utf-8", []byte(notification.Message), headers)
+}
@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"errors"
+	"strings"
+)
+
+// slackEscaper escapes the control characters, so messages can't contain mentions such as <!channel>
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+type SlackNotifier struct {
+	webhookURL string
+}
+
+func NewSlackNotifier(webhookURL string) (*SlackNotifier, error) {
+	if webhookURL == "" {
+		return nil, errors.New("slack webhook URL is required")
+	}
+	return &SlackNotifier{webhookURL: webhookURL}, nil
+}
+
+func (n *SlackNotifier) Send(notification Notification) error {
+	return postJSON(n.webhookURL, map[string]interface{}{
+		"text": "*" + slackEscaper.Replace(notification.Title) + "*\n" + slackEscaper.Replace(notification.Message),
+	}, nil)
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// WebhookNotifier posts the event as JSON. When a secret is set the request is signed with
+// X-Webhook-Signature: sha256=HMAC-SHA256(secret, "<X-Webhook-Timestamp>.<body>")
+type WebhookNotifier struct {
+	url    string
+	secret string
+}
+
+func NewWebhookNotifier(url string, secret string) (*WebhookNotifier, error) {
+	if url == "" {
+		return nil, errors.New("webhook URL is required")
+	}
+	return &WebhookNotifier{url: url, secret: secret}, nil
+}
+
+func (n *WebhookNotifier) Send(notification Notification) error {
+	timestamp := time.Now().Unix()
+
+	data := notification.Data
+	if len(data) == 0 {
+		data = json.RawMessage("null")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event":     notification.Event,
+		"title":     notification.Title,
+		"message":   notification.Message,
+		"data":      data,
+		"timestamp": timestamp,
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{
+		"X-Webhook-Event":     notification.Event,
+		"X-Webhook-Timestamp": strconv.FormatInt(timestamp, 10),
+	}
+
+	if n.secret != "" {
+		headers["X-Webhook-Signature"] = "sha256=" + SignWebhook(n.secret, timestamp, body)
+	}
+
+	return post(n.url, "application/json", body, headers)
+}
+
+// SignWebhook returns the hex signature receivers should compare against X-Webhook-Signature
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
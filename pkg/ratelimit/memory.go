@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps buckets in process, limits are per replica
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will be full again, after which it can be forgotten
+	full time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now()}
+}
+
+func (s *MemoryStore) Take(key string, policy Policy) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	capacity := policy.capacity()
+	rate := policy.tokensPerSecond()
+
+	// Each policy has its own buckets, like the Redis store's keys
+	key = policy.Name + ":" + key
+	b, exists := s.buckets[key]
+	if !exists {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	result := policy.result(allowed, b.tokens)
+	b.full = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// sweep forgets buckets that have refilled, since they behave the same as a new bucket
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.After(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type KeyBy string
+
+const (
+	KEY_BY_IP KeyBy = "IP"
+	// KEY_BY_USER limits signed in users by their ID, anonymous requests fall back to their IP
+	KEY_BY_USER KeyBy = "USER"
+	// KEY_BY_API_KEY limits by the X-API-Key header, requests without one fall back to their IP
+	KEY_BY_API_KEY KeyBy = "API_KEY"
+)
+
+// Policy is a token bucket holding Burst tokens, refilled at Limit tokens per Period
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+	Burst  int
+	KeyBy  KeyBy
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, 0 when allowed
+	RetryAfter time.Duration
+}
+
+// Store is implemented by the bucket storage backends (memory, Redis)
+type Store interface {
+	// Take removes a token from the key's bucket if one is available
+	Take(key string, policy Policy) (Result, error)
+}
+
+func (p Policy) capacity() float64 {
+	if p.Burst > 0 {
+		return float64(p.Burst)
+	}
+	return float64(p.Limit)
+}
+
+// tokensPerSecond is the refill rate of the bucket
+func (p Policy) tokensPerSecond() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// result builds the Result for the tokens left in the bucket after a take
+func (p Policy) result(allowed bool, tokens float64) Result {
+	rate := p.tokensPerSecond()
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     int(p.capacity()),
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((p.capacity() - tokens) / rate),
+	}
+
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+
+	return result
+}
+
+// ParsePolicy parses "<limit>/<period>" followed by optional ",burst=<n>" and ",by=<ip|user|api_key>"
+// options, e.g. "5/1h" or "60/1m,burst=10,by=user"
+func ParsePolicy(name string, value string, keyBy KeyBy) (Policy, error) {
+	policy := Policy{Name: name, KeyBy: keyBy}
+
+	options := strings.Split(value, ",")
+
+	limit, period, found := strings.Cut(strings.TrimSpace(options[0]), "/")
+	if !found {
+		return policy, fmt.Errorf("invalid rate limit %q, expected <limit>/<period>", value)
+	}
+
+	var err error
+	if policy.Limit, err = strconv.Atoi(limit); err != nil || policy.Limit < 1 {
+		return policy, fmt.Errorf("invalid rate limit %q, limit must be a positive number", value)
+	}
+
+	if policy.Period, err = time.ParseDuration(period); err != nil || policy.Period <= 0 {
+		return policy, fmt.Errorf("invalid rate limit %q, period must be a duration such as 1m", value)
+	}
+
+	for _, option := range options[1:] {
+		optionName, optionValue, _ := strings.Cut(strings.TrimSpace(option), "=")
+
+		switch optionName {
+		case "burst":
+			if policy.Burst, err = strconv.Atoi(optionValue); err != nil || policy.Burst < 1 {
+				return policy, fmt.Errorf("invalid rate limit %q, burst must be a positive number", value)
+			}
+		case "by":
+			switch KeyBy(strings.ToUpper(optionValue)) {
+			case KEY_BY_IP, KEY_BY_USER, KEY_BY_API_KEY:
+				policy.KeyBy = KeyBy(strings.ToUpper(optionValue))
+			default:
+				return policy, fmt.Errorf("invalid rate limit %q, by must be ip, user or api_key", value)
+			}
+		default:
+			return policy, fmt.Errorf("invalid rate limit %q, unknown option %q", value, optionName)
+		}
+	}
+
+	return policy, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScriptSource refills and takes from the bucket atomically, using the server clock so
+// replicas with drifting clocks share the same buckets. Returns {allowed, tokens left}.
+const tokenBucketScriptSource = `
+if redis.replicate_commands then redis.replicate_commands() end
+
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or capacity
+local updated = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - updated) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`
+
+// tokenBucketScript is sent with EVALSHA, falling back to EVAL when the server hasn't cached it yet
+var tokenBucketScript = redis.NewScript(tokenBucketScriptSource)
+
+// RedisStore keeps buckets in Redis (or a compatible server) so limits are shared between replicas
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Take(key string, policy Policy) (Result, error) {
+	values, err := tokenBucketScript.Run(context.Background(), s.client,
+		[]string{s.prefix + policy.Name + ":" + key},
+		strconv.FormatFloat(policy.capacity(), 'f', -1, 64),
+		strconv.FormatFloat(policy.tokensPerSecond(), 'f', -1, 64),
+	).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit script reply %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	tokensValue, ok := values[1].(string)
+	if !ok {
+		return Result{}, fmt.Errorf("unexpected rate limit script reply %v", values)
+	}
+
+	tokens, err := strconv.ParseFloat(tokensValue, 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return policy.result(allowed == 1, tokens), nil
+}
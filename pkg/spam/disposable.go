@@ -0,0 +1,21 @@
+package spam
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed disposable_domains.txt
+var disposableDomainList string
+
+// DisposableDomains returns the built-in disposable email domains merged with the extra domains
+func DisposableDomains(extra ...string) map[string]bool {
+	domains := make(map[string]bool)
+	for _, domain := range append(strings.Split(disposableDomainList, "\n"), extra...) {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" && !strings.HasPrefix(domain, "#") {
+			domains[domain] = true
+		}
+	}
+	return domains
+}
@@ -0,0 +1,136 @@
+package spam
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// HoneypotFilter flags submissions that filled in a field hidden from humans
+type HoneypotFilter struct{}
+
+func (f *HoneypotFilter) Name() string {
+	return "honeypot"
+}
+
+func (f *HoneypotFilter) Check(submission Submission) *Signal {
+	if strings.TrimSpace(submission.Honeypot) == "" {
+		return nil
+	}
+	return &Signal{Score: 1, Reason: "hidden field was filled in"}
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://|\bwww\.|\[url[=\]]|<a\s+href`)
+
+// LinkFilter scores every link above MaxLinks
+type LinkFilter struct {
+	MaxLinks     int
+	ScorePerLink float64
+}
+
+func (f *LinkFilter) Name() string {
+	return "links"
+}
+
+func (f *LinkFilter) Check(submission Submission) *Signal {
+	links := len(linkPattern.FindAllStringIndex(submission.Message, -1))
+	if links <= f.MaxLinks {
+		return nil
+	}
+
+	return &Signal{
+		Score:  float64(links-f.MaxLinks) * f.ScorePerLink,
+		Reason: fmt.Sprintf("message contains %d links", links),
+	}
+}
+
+// BlocklistFilter scores every blocked keyword or pattern found in the name or message
+type BlocklistFilter struct {
+	Keywords    []string
+	Patterns    []*regexp.Regexp
+	ScorePerHit float64
+}
+
+func (f *BlocklistFilter) Name() string {
+	return "blocklist"
+}
+
+func (f *BlocklistFilter) Check(submission Submission) *Signal {
+	content := strings.ToLower(submission.Name + "\n" + submission.Message)
+
+	var hits []string
+	for _, keyword := range f.Keywords {
+		if keyword != "" && strings.Contains(content, strings.ToLower(keyword)) {
+			hits = append(hits, keyword)
+		}
+	}
+
+	for _, pattern := range f.Patterns {
+		if pattern.MatchString(submission.Name + "\n" + submission.Message) {
+			hits = append(hits, pattern.String())
+		}
+	}
+
+	if len(hits) == 0 {
+		return nil
+	}
+
+	return &Signal{
+		Score:  float64(len(hits)) * f.ScorePerHit,
+		Reason: "matched " + strings.Join(hits, ", "),
+	}
+}
+
+// DisposableEmailFilter flags addresses at throwaway mailbox providers, including their subdomains
+type DisposableEmailFilter struct {
+	Domains map[string]bool
+	Score   float64
+}
+
+func (f *DisposableEmailFilter) Name() string {
+	return "disposableEmail"
+}
+
+func (f *DisposableEmailFilter) Check(submission Submission) *Signal {
+	at := strings.LastIndex(submission.Email, "@")
+	if at == -1 {
+		return nil
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(submission.Email[at+1:]))
+	for domain != "" {
+		if f.Domains[domain] {
+			return &Signal{Score: f.Score, Reason: domain + " is a disposable email domain"}
+		}
+
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			break
+		}
+		domain = parent
+	}
+
+	return nil
+}
+
+// DuplicateFilter flags messages already received within Window. CountRecent returns how many
+// stored messages share the hash since the given time, so the filter doesn't depend on the database.
+type DuplicateFilter struct {
+	Window      time.Duration
+	Score       float64
+	CountRecent func(messageHash string, since time.Time) (int64, error)
+}
+
+func (f *DuplicateFilter) Name() string {
+	return "duplicate"
+}
+
+func (f *DuplicateFilter) Check(submission Submission) *Signal {
+	count, err := f.CountRecent(MessageHash(submission.Message), time.Now().Add(-f.Window))
+	if err != nil || count == 0 {
+		return nil
+	}
+
+	return &Signal{Score: f.Score, Reason: fmt.Sprintf("same message received %d times in the last %s", count, f.Window)}
+}
@@ -0,0 +1,97 @@
+package spam
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormTokenFilter rejects submissions sent faster than a human could fill in the form,
+// using a signed single-use token holding the time the form was loaded
+type FormTokenFilter struct {
+	Secret []byte
+	MinAge time.Duration
+	MaxAge time.Duration
+	// MissingScore is how suspicious a submission without a token is, leaving it out mustn't skip the check
+	MissingScore float64
+	// Claim records the token's nonce for ttl, returning false when it was already used. Optional, a
+	// failing claim doesn't block the message.
+	Claim func(nonce string, ttl time.Duration) (bool, error)
+}
+
+// IssueFormToken returns a token for a form loaded now, as "<unix seconds>.<nonce>.<signature>"
+func IssueFormToken(secret []byte, now time.Time) string {
+	random := make([]byte, 16)
+	rand.Read(random)
+
+	payload := strconv.FormatInt(now.Unix(), 10) + "." + hex.EncodeToString(random)
+	return payload + "." + signFormToken(secret, payload)
+}
+
+func (f *FormTokenFilter) Name() string {
+	return "formToken"
+}
+
+func (f *FormTokenFilter) Check(submission Submission) *Signal {
+	if submission.FormToken == "" {
+		if f.MissingScore <= 0 {
+			return nil
+		}
+		return &Signal{Score: f.MissingScore, Reason: "form token is missing"}
+	}
+
+	issuedAt, nonce, err := f.parse(submission.FormToken)
+	if err != nil {
+		return &Signal{Score: 1, Reason: err.Error()}
+	}
+
+	age := time.Since(issuedAt)
+	if age < f.MinAge {
+		return &Signal{Score: 1, Reason: fmt.Sprintf("submitted %s after loading the form", age.Round(100*time.Millisecond))}
+	}
+
+	if f.MaxAge > 0 && age > f.MaxAge {
+		return &Signal{Score: 0.5, Reason: "form token has expired"}
+	}
+
+	// Expired tokens are caught above, so the nonce only needs remembering until then
+	if f.Claim != nil {
+		claimed, err := f.Claim(nonce, f.MaxAge-age)
+		if err == nil && !claimed {
+			return &Signal{Score: 1, Reason: "form token was already used"}
+		}
+	}
+
+	return nil
+}
+
+func (f *FormTokenFilter) parse(token string) (time.Time, string, error) {
+	separator := strings.LastIndex(token, ".")
+	if separator < 0 {
+		return time.Time{}, "", fmt.Errorf("form token is malformed")
+	}
+	payload, signature := token[:separator], token[separator+1:]
+
+	if !hmac.Equal([]byte(signature), []byte(signFormToken(f.Secret, payload))) {
+		return time.Time{}, "", fmt.Errorf("form token signature is invalid")
+	}
+
+	issuedAt, nonce, found := strings.Cut(payload, ".")
+	seconds, err := strconv.ParseInt(issuedAt, 10, 64)
+	if !found || err != nil || nonce == "" {
+		return time.Time{}, "", fmt.Errorf("form token is malformed")
+	}
+
+	return time.Unix(seconds, 0), nonce, nil
+}
+
+func signFormToken(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("contact-form\n" + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
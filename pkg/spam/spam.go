@@ -0,0 +1,79 @@
+package spam
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Submission is a contact form submission as seen by the filters
+type Submission struct {
+	Name      string
+	Email     string
+	Message   string
+	Honeypot  string
+	FormToken string
+}
+
+// Signal is produced by a filter that found something suspicious, a score of 1 or more is spam on its own
+type Signal struct {
+	Filter string  `json:"filter"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// Filter is implemented by every layer of the pipeline, Check returns nil when nothing was found
+type Filter interface {
+	Name() string
+	Check(submission Submission) *Signal
+}
+
+type Verdict struct {
+	Score   float64  `json:"score"`
+	IsSpam  bool     `json:"isSpam"`
+	Signals []Signal `json:"signals"`
+}
+
+func (v Verdict) Reasons() []string {
+	reasons := make([]string, 0, len(v.Signals))
+	for _, signal := range v.Signals {
+		reasons = append(reasons, signal.Filter+": "+signal.Reason)
+	}
+	return reasons
+}
+
+// Pipeline runs every filter and adds up their scores, submissions reaching the threshold are spam
+type Pipeline struct {
+	filters   []Filter
+	threshold float64
+}
+
+func NewPipeline(threshold float64, filters ...Filter) *Pipeline {
+	return &Pipeline{filters: filters, threshold: threshold}
+}
+
+func (p *Pipeline) Check(submission Submission) Verdict {
+	verdict := Verdict{Signals: []Signal{}}
+
+	for _, filter := range p.filters {
+		signal := filter.Check(submission)
+		if signal == nil {
+			continue
+		}
+
+		signal.Filter = filter.Name()
+		verdict.Score += signal.Score
+		verdict.Signals = append(verdict.Signals, *signal)
+	}
+
+	verdict.IsSpam = verdict.Score >= p.threshold
+
+	return verdict
+}
+
+// MessageHash identifies a message regardless of case and whitespace, for duplicate detection
+func MessageHash(message string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(message)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
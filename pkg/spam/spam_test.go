@@ -0,0 +1,231 @@
+package spam
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("secret")
+
+func TestFormTokenFilter(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		token        string
+		missingScore float64
+		wantScore    float64
+	}{
+		{"missing token without a score", "", 0, 0},
+		{"missing token scores like a too fast submission", "", 1, 1},
+		{"filled in after the minimum age", IssueFormToken(testSecret, now.Add(-10*time.Second)), 0, 0},
+		{"submitted too fast", IssueFormToken(testSecret, now), 0, 1},
+		{"expired", IssueFormToken(testSecret, now.Add(-48*time.Hour)), 0, 0.5},
+		{"signed with another secret", IssueFormToken([]byte("other"), now.Add(-10*time.Second)), 0, 1},
+		{"malformed", "not-a-token", 0, 1},
+		{"nonce changed after signing", tamperNonce(IssueFormToken(testSecret, now.Add(-10*time.Second))), 0, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter := &FormTokenFilter{Secret: testSecret, MinAge: 3 * time.Second, MaxAge: 24 * time.Hour, MissingScore: test.missingScore}
+
+			if got := signalScore(filter.Check(Submission{FormToken: test.token})); got != test.wantScore {
+				t.Errorf("score = %v, want %v", got, test.wantScore)
+			}
+		})
+	}
+}
+
+// tamperNonce swaps the token's nonce for another one, keeping the signature
+func tamperNonce(token string) string {
+	parts := strings.Split(token, ".")
+	parts[1] = strings.Repeat("0", len(parts[1]))
+	return strings.Join(parts, ".")
+}
+
+func TestFormTokenSingleUse(t *testing.T) {
+	claimed := make(map[string]time.Duration)
+	filter := &FormTokenFilter{
+		Secret: testSecret,
+		MinAge: 3 * time.Second,
+		MaxAge: time.Hour,
+		Claim: func(nonce string, ttl time.Duration) (bool, error) {
+			if _, used := claimed[nonce]; used {
+				return false, nil
+			}
+			claimed[nonce] = ttl
+			return true, nil
+		},
+	}
+
+	token := IssueFormToken(testSecret, time.Now().Add(-10*time.Second))
+	if got := signalScore(filter.Check(Submission{FormToken: token})); got != 0 {
+		t.Fatalf("first use score = %v, want 0", got)
+	}
+	if got := signalScore(filter.Check(Submission{FormToken: token})); got != 1 {
+		t.Errorf("second use score = %v, want 1", got)
+	}
+
+	// The nonce is only remembered until the token expires
+	for _, ttl := range claimed {
+		if ttl <= 59*time.Minute || ttl > time.Hour {
+			t.Errorf("claim ttl = %v, want about 59m50s", ttl)
+		}
+	}
+
+	// A fresh token is a fresh nonce
+	if got := signalScore(filter.Check(Submission{FormToken: IssueFormToken(testSecret, time.Now().Add(-10*time.Second))})); got != 0 {
+		t.Errorf("new token score = %v, want 0", got)
+	}
+
+	// A failing claim doesn't block the message
+	filter.Claim = func(string, time.Duration) (bool, error) { return false, errors.New("store is down") }
+	if got := signalScore(filter.Check(Submission{FormToken: token})); got != 0 {
+		t.Errorf("score with a failing claim = %v, want 0", got)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     Filter
+		submission Submission
+		wantScore  float64
+	}{
+		{"honeypot empty", &HoneypotFilter{}, Submission{Honeypot: "  "}, 0},
+		{"honeypot filled", &HoneypotFilter{}, Submission{Honeypot: "https://spam.example"}, 1},
+
+		{"links within limit", &LinkFilter{MaxLinks: 2, ScorePerLink: 0.25}, Submission{Message: "see https://a.example and www.b.example"}, 0},
+		{"links over limit", &LinkFilter{MaxLinks: 2, ScorePerLink: 0.25}, Submission{Message: "http://a https://b www.c [url=d] <a href=e>"}, 0.75},
+
+		{"blocklist keyword, any case", &BlocklistFilter{Keywords: []string{"Crypto"}, ScorePerHit: 0.5}, Submission{Message: "CRYPTO deals"}, 0.5},
+		{"blocklist name and pattern", &BlocklistFilter{Keywords: []string{"seo"}, Patterns: []*regexp.Regexp{regexp.MustCompile(`(?i)invest\s+now`)}, ScorePerHit: 0.5},
+			Submission{Name: "SEO expert", Message: "Invest   now"}, 1},
+		{"blocklist clean", &BlocklistFilter{Keywords: []string{"casino"}, ScorePerHit: 0.5}, Submission{Message: "Hello"}, 0},
+
+		{"disposable domain", &DisposableEmailFilter{Domains: DisposableDomains("mailinator.com"), Score: 0.6}, Submission{Email: "a@Mailinator.com"}, 0.6},
+		{"disposable subdomain", &DisposableEmailFilter{Domains: DisposableDomains("mailinator.com"), Score: 0.6}, Submission{Email: "a@eu.mailinator.com"}, 0.6},
+		{"regular domain", &DisposableEmailFilter{Domains: map[string]bool{"mailinator.com": true}, Score: 0.6}, Submission{Email: "a@example.com"}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := signalScore(test.filter.Check(test.submission)); math.Abs(got-test.wantScore) > 1e-9 {
+				t.Errorf("score = %v, want %v", got, test.wantScore)
+			}
+		})
+	}
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	var gotHash string
+	filter := &DuplicateFilter{
+		Window: time.Hour,
+		Score:  0.6,
+		CountRecent: func(messageHash string, since time.Time) (int64, error) {
+			gotHash = messageHash
+			return 2, nil
+		},
+	}
+
+	if got := signalScore(filter.Check(Submission{Message: "  Hello\n WORLD "})); got != 0.6 {
+		t.Errorf("score = %v, want 0.6", got)
+	}
+	if gotHash != MessageHash("hello world") {
+		t.Error("duplicate lookup didn't use the normalised message hash")
+	}
+
+	// A failing lookup doesn't block the message
+	filter.CountRecent = func(string, time.Time) (int64, error) { return 0, errors.New("database is down") }
+	if signal := filter.Check(Submission{Message: "Hello"}); signal != nil {
+		t.Errorf("signal = %+v, want nil", signal)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	pipeline := NewPipeline(1,
+		&HoneypotFilter{},
+		&FormTokenFilter{Secret: testSecret, MinAge: 3 * time.Second, MaxAge: 24 * time.Hour, MissingScore: 1},
+		&LinkFilter{MaxLinks: 1, ScorePerLink: 0.25},
+		&DisposableEmailFilter{Domains: DisposableDomains("mailinator.com"), Score: 0.6},
+	)
+
+	// A new token per submission, as tokens are single-use when the filter has a Claim
+	formToken := func() string { return IssueFormToken(testSecret, time.Now().Add(-time.Minute)) }
+
+	tests := []struct {
+		name        string
+		submission  Submission
+		wantScore   float64
+		wantSpam    bool
+		wantFilters []string
+	}{
+		{
+			name:        "clean submission",
+			submission:  Submission{Name: "Jane", Email: "jane@example.com", Message: "Hello https://example.com", FormToken: formToken()},
+			wantFilters: []string{},
+		},
+		{
+			name:        "missing form token",
+			submission:  Submission{Name: "Jane", Email: "jane@example.com", Message: "Hello"},
+			wantScore:   1,
+			wantSpam:    true,
+			wantFilters: []string{"formToken"},
+		},
+		{
+			name:        "signals below the threshold",
+			submission:  Submission{Email: "a@mailinator.com", Message: "http://a http://b", FormToken: formToken()},
+			wantScore:   0.85,
+			wantFilters: []string{"links", "disposableEmail"},
+		},
+		{
+			name:        "signals adding up to the threshold",
+			submission:  Submission{Email: "a@mailinator.com", Message: "http://a http://b http://c", FormToken: formToken()},
+			wantScore:   1.1,
+			wantSpam:    true,
+			wantFilters: []string{"links", "disposableEmail"},
+		},
+		{
+			name:        "single decisive signal",
+			submission:  Submission{Honeypot: "x", FormToken: formToken()},
+			wantScore:   1,
+			wantSpam:    true,
+			wantFilters: []string{"honeypot"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verdict := pipeline.Check(test.submission)
+
+			if math.Abs(verdict.Score-test.wantScore) > 1e-9 || verdict.IsSpam != test.wantSpam {
+				t.Errorf("verdict = score %v, spam %v; want score %v, spam %v", verdict.Score, verdict.IsSpam, test.wantScore, test.wantSpam)
+			}
+
+			if len(verdict.Signals) != len(test.wantFilters) {
+				t.Fatalf("signals = %+v, want filters %v", verdict.Signals, test.wantFilters)
+			}
+			for i, signal := range verdict.Signals {
+				if signal.Filter != test.wantFilters[i] {
+					t.Errorf("signal %d filter = %q, want %q", i, signal.Filter, test.wantFilters[i])
+				}
+			}
+
+			if reasons := verdict.Reasons(); len(reasons) != len(verdict.Signals) {
+				t.Errorf("Reasons() = %v", reasons)
+			}
+		})
+	}
+}
+
+func signalScore(signal *Signal) float64 {
+	if signal == nil {
+		return 0
+	}
+	return signal.Score
+}
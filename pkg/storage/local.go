@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalRoutePrefix is the route the API serves signed local uploads and downloads from
+const LocalRoutePrefix = "/storage/local/"
+
+const localTempPrefix = ".upload-"
+
+// LocalStorage keeps objects on the local filesystem and hands out signed URLs
+// pointing back at the API, so no S3 server is needed for development
+type LocalStorage struct {
+	root        string
+	baseURL     string
+	secret      []byte
+	downloadTTL time.Duration
+	public      publicBaseURL
+}
+
+type LocalConfig struct {
+	Root string
+	// BaseURL is the public address of this API, used to build the signed upload and download URLs
+	BaseURL     string
+	Secret      []byte
+	DownloadTTL time.Duration
+	// PublicBaseURL is optional, when set download URLs point at it instead of the signed API route. The API
+	// only serves signed requests, so it must be a web server or CDN reading straight from Root.
+	PublicBaseURL string
+}
+
+func NewLocalStorage(config LocalConfig) (*LocalStorage, error) {
+	root := config.Root
+	downloadTTL := config.DownloadTTL
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %v", err)
+	}
+
+	if downloadTTL <= 0 {
+		downloadTTL = 24 * time.Hour
+	}
+
+	return &LocalStorage{
+		root:        root,
+		baseURL:     strings.TrimSuffix(config.BaseURL, "/"),
+		secret:      config.Secret,
+		downloadTTL: downloadTTL,
+		public:      publicBaseURL(config.PublicBaseURL),
+	}, nil
+}
+
+func (s *LocalStorage) PresignPut(key string, expires time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	return s.signedURL(http.MethodPut, key, time.Now().Add(expires)), nil
+}
+
+func (s *LocalStorage) Put(key string, body io.Reader, contentType string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("failed to create object directory: %v", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial object
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), localTempPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to create object file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := io.Copy(tempFile, body); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write object: %v", err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to write object: %v", err)
+	}
+
+	if err := os.Rename(tempFile.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to store object: %v", err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Open(key string) (io.ReadCloser, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return file, err
+}
+
+func (s *LocalStorage) Head(key string) (*ObjectInfo, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.IsDir() {
+		return nil, ErrNotFound
+	}
+
+	// The filesystem has no content type metadata, so sniff it from the first bytes
+	buffer := make([]byte, 512)
+	n, _ := io.ReadFull(file, buffer)
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         fileInfo.Size(),
+		ContentType:  http.DetectContentType(buffer[:n]),
+		LastModified: fileInfo.ModTime(),
+	}, nil
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object: %v", err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) List(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	err := filepath.WalkDir(s.root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), localTempPrefix) {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(s.root, filePath)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(relativePath)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         fileInfo.Size(),
+			LastModified: fileInfo.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %v", err)
+	}
+
+	return objects, nil
+}
+
+// PublicURL returns a signed download URL. The expiry is rounded to the TTL window
+// so the URL stays stable (and cacheable) between requests.
+func (s *LocalStorage) PublicURL(key string) string {
+	if s.public != "" {
+		return s.public.url(key)
+	}
+
+	expires := time.Now().Truncate(s.downloadTTL).Add(2 * s.downloadTTL)
+	return s.signedURL(http.MethodGet, key, expires)
+}
+
+func (s *LocalStorage) KeyFromURL(rawURL string) (string, error) {
+	if s.public != "" {
+		if key, err := s.public.keyFromURL(rawURL); err == nil {
+			return key, nil
+		}
+	}
+	return keyFromBaseURL(s.baseURL+LocalRoutePrefix, rawURL)
+}
+
+// VerifySignature checks a signature produced by PresignPut or PublicURL for the given method
+func (s *LocalStorage) VerifySignature(method string, key string, expires string, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expiry")
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return fmt.Errorf("signature has expired")
+	}
+
+	expected := s.sign(method, key, expiresAt)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("invalid signature")
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) signedURL(method string, key string, expires time.Time) string {
+	return fmt.Sprintf("%s%s%s?expires=%d&signature=%s", s.baseURL, LocalRoutePrefix, escapeKey(key), expires.Unix(), s.sign(method, key, expires.Unix()))
+}
+
+func (s *LocalStorage) sign(method string, key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps an object key to a file path, rejecting keys that would escape the storage root
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned == "/" || cleaned != "/"+key {
+		return "", fmt.Errorf("invalid object key: %q", key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
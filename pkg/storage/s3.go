@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+type S3Config struct {
+	Endpoint  string // host:port of the S3-compatible server
+	UseSSL    bool
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicBaseURL is optional, when set public URLs are built from it (e.g. a CDN) instead of the endpoint
+	PublicBaseURL string
+	// ServerSideEncryption is the x-amz-server-side-encryption applied to uploads, e.g. AES256 (empty for none)
+	ServerSideEncryption string
+}
+
+type S3Storage struct {
+	client   *s3.S3
+	uploader *s3manager.Uploader
+	bucket   string
+	endpoint string
+	public   publicBaseURL
+	sse      *string
+}
+
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	endpoint := "http://" + config.Endpoint
+	if config.UseSSL {
+		endpoint = "https://" + config.Endpoint
+	}
+
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:           aws.String(region),
+		Endpoint:         aws.String(endpoint),
+		Credentials:      credentials.NewStaticCredentials(config.AccessKey, config.SecretKey, ""),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 session: %v", err)
+	}
+
+	client := s3.New(sess)
+
+	var sse *string
+	if config.ServerSideEncryption != "" {
+		sse = aws.String(config.ServerSideEncryption)
+	}
+
+	return &S3Storage{
+		client:   client,
+		uploader: s3manager.NewUploaderWithClient(client),
+		bucket:   config.Bucket,
+		endpoint: endpoint,
+		public:   publicBaseURL(config.PublicBaseURL),
+		sse:      sse,
+	}, nil
+}
+
+// PresignPut signs the server-side encryption header too, so clients must send it with the same value
+func (s *S3Storage) PresignPut(key string, expires time.Duration) (string, error) {
+	req, _ := s.client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(key),
+		ServerSideEncryption: s.sse,
+	})
+
+	urlStr, err := req.Presign(expires)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign request: %v", err)
+	}
+
+	return urlStr, nil
+}
+
+// Put streams the body to S3 using a multipart upload, so the size does not need to be known
+func (s *S3Storage) Put(key string, body io.Reader, contentType string) error {
+	_, err := s.uploader.Upload(&s3manager.UploadInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(key),
+		Body:                 body,
+		ContentType:          aws.String(contentType),
+		ServerSideEncryption: s.sse,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %v", err)
+	}
+
+	return nil
+}
+
+func (s *S3Storage) Open(key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, mapS3Error(err)
+	}
+
+	return output.Body, nil
+}
+
+func (s *S3Storage) Head(key string) (*ObjectInfo, error) {
+	output, err := s.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, mapS3Error(err)
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(output.ContentLength),
+		ContentType:  aws.StringValue(output.ContentType),
+		LastModified: aws.TimeValue(output.LastModified),
+	}, nil
+}
+
+func (s *S3Storage) Delete(key string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %v", err)
+	}
+
+	return nil
+}
+
+func (s *S3Storage) List(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %v", err)
+	}
+
+	return objects, nil
+}
+
+func (s *S3Storage) PublicURL(key string) string {
+	if s.public != "" {
+		return s.public.url(key)
+	}
+	return s.bucketURL() + escapeKey(key)
+}
+
+func (s *S3Storage) KeyFromURL(rawURL string) (string, error) {
+	if s.public != "" {
+		if key, err := s.public.keyFromURL(rawURL); err == nil {
+			return key, nil
+		}
+	}
+	return keyFromBaseURL(s.bucketURL(), rawURL)
+}
+
+func (s *S3Storage) bucketURL() string {
+	return s.endpoint + "/" + s.bucket + "/"
+}
+
+func mapS3Error(err error) error {
+	if awsErr, ok := err.(awserr.Error); ok {
+		switch awsErr.Code() {
+		case s3.ErrCodeNoSuchKey, "NotFound":
+			return ErrNotFound
+		}
+	}
+	return err
+}
+
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// keyFromBaseURL checks that rawURL lives under baseURL and returns the decoded remainder of the path
+func keyFromBaseURL(baseURL string, rawURL string) (string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %v", err)
+	}
+
+	parsedBase, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse base URL: %v", err)
+	}
+
+	basePath := strings.TrimSuffix(parsedBase.Path, "/") + "/"
+	if parsedURL.Scheme != parsedBase.Scheme || parsedURL.Host != parsedBase.Host || !strings.HasPrefix(parsedURL.Path, basePath) {
+		return "", fmt.Errorf("URL does not match the expected storage endpoint: %s", baseURL)
+	}
+
+	key := strings.TrimPrefix(parsedURL.Path, basePath)
+	if key == "" {
+		return "", fmt.Errorf("URL does not contain an object key")
+	}
+
+	return key, nil
+}
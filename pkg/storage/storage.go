@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"contentType"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// Storage is implemented by every object storage driver (S3, local filesystem)
+type Storage interface {
+	// PresignPut returns a URL that a client can PUT the object body to
+	PresignPut(key string, expires time.Duration) (string, error)
+	Put(key string, body io.Reader, contentType string) error
+	Open(key string) (io.ReadCloser, error)
+	// Head returns ErrNotFound if the object does not exist
+	Head(key string) (*ObjectInfo, error)
+	Delete(key string) error
+	List(prefix string) ([]ObjectInfo, error)
+	PublicURL(key string) string
+	// KeyFromURL extracts the object key from a URL produced by PublicURL or PresignPut
+	KeyFromURL(rawURL string) (string, error)
+}
+
+// publicBaseURL is shared by the drivers to serve objects from a CDN or custom
+// domain instead of the storage endpoint itself
+type publicBaseURL string
+
+func (base publicBaseURL) url(key string) string {
+	return strings.TrimSuffix(string(base), "/") + "/" + escapeKey(key)
+}
+
+func (base publicBaseURL) keyFromURL(rawURL string) (string, error) {
+	return keyFromBaseURL(strings.TrimSuffix(string(base), "/")+"/", rawURL)
+}
@@ -0,0 +1,43 @@
+package structs
+
+import (
+	"gorm.io/gorm"
+)
+
+// PublicURL builds the public URL of a stored object key. It is replaced by
+// initializers.InitializeStorage with the configured storage driver.
+var PublicURL = func(key string) string { return key }
+
+func (p *ProjectImages) AfterFind(tx *gorm.DB) error {
+	p.ImageURL = PublicURL(p.ImageKey)
+	return nil
+}
+
+func (p *ProjectImages) AfterSave(tx *gorm.DB) error {
+	p.ImageURL = PublicURL(p.ImageKey)
+	return nil
+}
+
+func (t *Technologies) AfterFind(tx *gorm.DB) error {
+	if t.TechnologyImageKey != "" {
+		t.TechnologyImage = PublicURL(t.TechnologyImageKey)
+	}
+	return nil
+}
+
+func (t *Technologies) AfterSave(tx *gorm.DB) error {
+	if t.TechnologyImageKey != "" {
+		t.TechnologyImage = PublicURL(t.TechnologyImageKey)
+	}
+	return nil
+}
+
+func (m *MediaAssets) AfterFind(tx *gorm.DB) error {
+	m.URL = PublicURL(m.ObjectKey)
+	return nil
+}
+
+func (m *MediaAssets) AfterSave(tx *gorm.DB) error {
+	m.URL = PublicURL(m.ObjectKey)
+	return nil
+}
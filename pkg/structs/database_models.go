@@ -47,6 +47,7 @@ type Projects struct {
 	ProjectImages       []ProjectImages       `json:"projectImages" gorm:"foreignKey:ProjectId"`       // One-to-many relationship
 	ProjectTechnologies []ProjectTechnologies `json:"projectTechnologies" gorm:"foreignKey:ProjectId"` // One-to-many relationship
 	ProjectURLs         ProjectURLs           `json:"projectURLs" gorm:"foreignKey:ProjectId"`         // One-to-one relationship
+	Repository          *ProjectRepositories  `json:"repository" gorm:"foreignKey:ProjectId"`          // One-to-one relationship, synced from ProjectURLs.GitHubURL
 }
 
 type ProjectURLs struct {
@@ -57,17 +58,45 @@ type ProjectURLs struct {
 	YouTubeURL string `json:"youtubeURL"`
 }
 
+// ProjectRepositories is a snapshot of the GitHub repository linked to a project
+type ProjectRepositories struct {
+	GormModel
+	ProjectId                uint       `json:"projectId" gorm:"uniqueIndex"`
+	Owner                    string     `json:"owner"`
+	Name                     string     `json:"name"`
+	URL                      string     `json:"url"`
+	Description              string     `json:"description"`
+	Language                 string     `json:"language"` // Primary language
+	Stars                    int        `json:"stars"`
+	Forks                    int        `json:"forks"`
+	OpenIssues               int        `json:"openIssues"`
+	LastCommitAt             *time.Time `json:"lastCommitAt"`
+	LatestReleaseTag         string     `json:"latestReleaseTag"`
+	LatestReleaseName        string     `json:"latestReleaseName"`
+	LatestReleaseURL         string     `json:"latestReleaseURL"`
+	LatestReleasePublishedAt *time.Time `json:"latestReleasePublishedAt"`
+	License                  string     `json:"license"` // SPDX identifier, e.g. MIT
+	LicenseName              string     `json:"licenseName"`
+	Topics                   []string   `json:"topics" gorm:"serializer:json;type:text"`
+	IsArchived               bool       `json:"isArchived"`
+	IsPrivate                bool       `json:"isPrivate"` // Private snapshots are left out of public responses
+	SyncedAt                 time.Time  `json:"syncedAt"`
+	SyncError                string     `json:"-" gorm:"type:text"`
+}
+
 type ProjectImages struct {
 	GormModel
 	ProjectId uint   `json:"projectId"`
-	ImageURL  string `json:"imageURL"`
+	ImageKey  string `json:"imageKey"`
+	ImageURL  string `json:"imageURL" gorm:"-"` // Built from ImageKey when loaded
 }
 
 type Technologies struct {
 	GormModel
-	TechnologyName  string         `json:"technologyName"`
-	TechnologyType  TechnologyType `json:"technologyType"`
-	TechnologyImage string         `json:"technologyImage"`
+	TechnologyName     string         `json:"technologyName"`
+	TechnologyType     TechnologyType `json:"technologyType"`
+	TechnologyImageKey string         `json:"technologyImageKey"`
+	TechnologyImage    string         `json:"technologyImage" gorm:"-"` // Built from TechnologyImageKey when loaded
 }
 
 type ProjectTechnologies struct {
@@ -76,7 +105,142 @@ type ProjectTechnologies struct {
 	TechnologyId uint `json:"technologyId"`
 }
 
+type MediaAssets struct {
+	GormModel
+	ObjectKey   string         `json:"objectKey" gorm:"type:varchar(512);uniqueIndex"`
+	Category    UploadCategory `json:"category" gorm:"type:varchar(64);index"`
+	Size        int64          `json:"size"`
+	ContentType string         `json:"contentType"`
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	ContentHash string         `json:"contentHash" gorm:"type:char(64);index"` // SHA-256 of the object, used for deduplication
+	UploadedAt  time.Time      `json:"uploadedAt"`
+	URL         string         `json:"url" gorm:"-"` // Built from ObjectKey when loaded
+}
+
+type ContactMessages struct {
+	GormModel
+	Name         string            `json:"name"`
+	Email        string            `json:"email"`
+	Message      string            `json:"message" gorm:"type:text"`
+	IPAddress    string            `json:"ipAddress"`
+	UserAgent    string            `json:"userAgent"`
+	CaptchaScore float64           `json:"captchaScore"`
+	IsRead       bool              `json:"isRead" gorm:"default:false"`
+	IsArchived   bool              `json:"isArchived" gorm:"default:false"`
+	IsStarred    bool              `json:"isStarred" gorm:"default:false"`
+	Fields       map[string]string `json:"fields" gorm:"serializer:json;type:text"` // Extra fields from the contact form schema
+	IsSpam       bool              `json:"isSpam" gorm:"default:false;index"`
+	SpamScore    float64           `json:"spamScore"`
+	SpamReasons  []string          `json:"spamReasons" gorm:"serializer:json;type:text"`
+	MessageHash  string            `json:"-" gorm:"type:char(64);index"`
+	Replies      []ContactReplies  `json:"replies" gorm:"foreignKey:MessageId"` // One-to-many relationship
+}
+
+type ContactReplies struct {
+	GormModel
+	MessageId uint   `json:"messageId"`
+	UserId    uint   `json:"userId"`
+	EmailId   uint   `json:"emailId"` // Outbound email the reply was queued as
+	Subject   string `json:"subject"`
+	Body      string `json:"body" gorm:"type:text"`
+}
+
+type OutboundEmails struct {
+	GormModel
+	Recipient     string                     `json:"recipient"`
+	ReplyTo       string                     `json:"replyTo"`
+	Subject       string                     `json:"subject"`
+	HTML          string                     `json:"html" gorm:"type:mediumtext"`
+	Text          string                     `json:"text" gorm:"type:mediumtext"`
+	Status        EmailStatus                `json:"status" gorm:"type:varchar(16);index;default:PENDING"`
+	Attempts      int                        `json:"attempts"`
+	MaxAttempts   int                        `json:"maxAttempts"`
+	NextAttemptAt time.Time                  `json:"nextAttemptAt" gorm:"index"`
+	LastError     string                     `json:"lastError" gorm:"type:text"`
+	SentAt        *time.Time                 `json:"sentAt"`
+	EmailAttempts []OutboundEmailAttempts    `json:"attemptLog,omitempty" gorm:"foreignKey:EmailId"`  // One-to-many relationship
+	Attachments   []OutboundEmailAttachments `json:"attachments,omitempty" gorm:"foreignKey:EmailId"` // One-to-many relationship
+}
+
+type OutboundEmailAttachments struct {
+	GormModel
+	EmailId     uint   `json:"emailId"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"-" gorm:"type:longblob"`
+}
+
+type OutboundEmailAttempts struct {
+	GormModel
+	EmailId   uint   `json:"emailId"`
+	Attempt   int    `json:"attempt"`
+	Succeeded bool   `json:"succeeded"`
+	Error     string `json:"error" gorm:"type:text"`
+}
+
+// Settings are admin editable values stored as JSON, see utils.GetSetting
+type Settings struct {
+	GormModel
+	Key   string `json:"key" gorm:"type:varchar(64);uniqueIndex"`
+	Value string `json:"value" gorm:"type:text"`
+}
+
+// CacheEntries persist cached responses (e.g. GitHub data) across restarts
+type CacheEntries struct {
+	ID        uint   `gorm:"primarykey"`
+	CacheKey  string `gorm:"type:varchar(191);uniqueIndex"`
+	Value     []byte `gorm:"type:longblob"`
+	StoredAt  time.Time
+	ExpiresAt time.Time `gorm:"index"`
+}
+
+type NotificationChannels struct {
+	GormModel
+	Name  string                  `json:"name"`
+	Type  NotificationChannelType `json:"type" gorm:"type:varchar(16)"`
+	URL   string                  `json:"url"`   // Webhook URL, or the ntfy server (ntfy.sh when empty)
+	Topic string                  `json:"topic"` // ntfy only
+	// Secret is the webhook signing key, or the ntfy access token
+	Secret    string `json:"-"`
+	Template  string `json:"template" gorm:"type:text"` // Empty uses the default template
+	IsEnabled bool   `json:"isEnabled"`
+}
+
+type NotificationDeliveries struct {
+	GormModel
+	ChannelId     uint                 `json:"channelId" gorm:"index"`
+	Channel       NotificationChannels `json:"-" gorm:"foreignKey:ChannelId"`
+	Event         string               `json:"event"`
+	Title         string               `json:"title"`
+	Message       string               `json:"message" gorm:"type:text"`
+	Data          string               `json:"data" gorm:"type:text"` // JSON event payload
+	Status        DeliveryStatus       `json:"status" gorm:"type:varchar(16);index;default:PENDING"`
+	Attempts      int                  `json:"attempts"`
+	MaxAttempts   int                  `json:"maxAttempts"`
+	NextAttemptAt time.Time            `json:"nextAttemptAt" gorm:"index"`
+	LastError     string               `json:"lastError" gorm:"type:text"`
+	SentAt        *time.Time           `json:"sentAt"`
+}
+
+type GitHubWebhookDeliveries struct {
+	GormModel
+	DeliveryID string                `json:"deliveryId" gorm:"type:varchar(64);uniqueIndex"` // X-GitHub-Delivery, redeliveries reuse it
+	Event      string                `json:"event" gorm:"type:varchar(64);index"`
+	Action     string                `json:"action"`
+	Repository string                `json:"repository"` // owner/name
+	Sender     string                `json:"sender"`
+	Status     WebhookDeliveryStatus `json:"status" gorm:"type:varchar(16);index"`
+	Reactions  []string              `json:"reactions" gorm:"serializer:json;type:text"` // What the delivery triggered
+	Error      string                `json:"error" gorm:"type:text"`
+	Payload    string                `json:"-" gorm:"type:mediumtext"`
+}
+
 type UploadCategory string
+type EmailStatus string
+type WebhookDeliveryStatus string
+type NotificationChannelType string
+type DeliveryStatus string
 type TechnologyType string
 type VerificationType string
 type UserRole string
@@ -86,6 +250,34 @@ const (
 	TECHNOLOGY_IMAGE UploadCategory = "TECHNOLOGY_IMAGE"
 )
 
+const (
+	EMAIL_PENDING EmailStatus = "PENDING"
+	EMAIL_SENDING EmailStatus = "SENDING"
+	EMAIL_SENT    EmailStatus = "SENT"
+	EMAIL_DEAD    EmailStatus = "DEAD" // Gave up after MaxAttempts failures
+)
+
+const (
+	DISCORD_CHANNEL NotificationChannelType = "DISCORD"
+	SLACK_CHANNEL   NotificationChannelType = "SLACK"
+	NTFY_CHANNEL    NotificationChannelType = "NTFY"
+	WEBHOOK_CHANNEL NotificationChannelType = "WEBHOOK"
+)
+
+const (
+	DELIVERY_PENDING DeliveryStatus = "PENDING"
+	DELIVERY_SENDING DeliveryStatus = "SENDING"
+	DELIVERY_SENT    DeliveryStatus = "SENT"
+	DELIVERY_DEAD    DeliveryStatus = "DEAD" // Gave up after MaxAttempts failures
+)
+
+const (
+	WEBHOOK_RECEIVED  WebhookDeliveryStatus = "RECEIVED"
+	WEBHOOK_PROCESSED WebhookDeliveryStatus = "PROCESSED"
+	WEBHOOK_IGNORED   WebhookDeliveryStatus = "IGNORED" // Unsupported event, or nothing matched it
+	WEBHOOK_FAILED    WebhookDeliveryStatus = "FAILED"
+)
+
 const (
 	LANGUAGE  TechnologyType = "LANGUAGE"
 	FRAMEWORK TechnologyType = "FRAMEWORK"
@@ -1,6 +1,6 @@
 package structs
 
-import ()
+import "time"
 
 type LoginResponseModel struct {
 	User  Users       `json:"user"`
@@ -11,3 +11,54 @@ type TokensModel struct {
 	AccessToken  string `json:"accessToken"`
 	RefreshToken string `json:"refreshToken"`
 }
+
+type MediaAssetModel struct {
+	MediaAssets
+	InUse bool            `json:"inUse"`
+	Usage MediaUsageModel `json:"usage"`
+}
+
+type MediaUsageModel struct {
+	Projects     []MediaUsageReference `json:"projects"`
+	Technologies []MediaUsageReference `json:"technologies"`
+}
+
+type MediaUsageReference struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type PaginationModel struct {
+	Page     int   `json:"page"`
+	PageSize int   `json:"pageSize"`
+	Total    int64 `json:"total"`
+}
+
+type MediaDuplicateGroupModel struct {
+	ContentHash string        `json:"contentHash"`
+	Canonical   MediaAssets   `json:"canonical"`
+	Duplicates  []MediaAssets `json:"duplicates"`
+}
+
+type ActivityType string
+
+const (
+	COMMIT_ACTIVITY       ActivityType = "COMMIT"
+	RELEASE_ACTIVITY      ActivityType = "RELEASE"
+	PULL_REQUEST_ACTIVITY ActivityType = "PULL_REQUEST" // Merged pull requests only
+)
+
+// ActivityEventModel is a commit, release or merged pull request in a project's linked repository
+type ActivityEventModel struct {
+	ID             string       `json:"id"` // Unique across event types, e.g. COMMIT:<sha>
+	Type           ActivityType `json:"type"`
+	Title          string       `json:"title"`
+	Ref            string       `json:"ref"` // Short commit SHA, release tag or #PR number
+	URL            string       `json:"url"`
+	Actor          string       `json:"actor"`
+	ActorAvatarURL string       `json:"actorAvatarURL"`
+	Repository     string       `json:"repository"` // owner/name
+	ProjectId      uint         `json:"projectId"`
+	ProjectName    string       `json:"projectName"`
+	OccurredAt     time.Time    `json:"occurredAt"`
+}
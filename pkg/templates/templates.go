@@ -0,0 +1,9 @@
+package templates
+
+import "embed"
+
+// Emails holds the default email templates, each can be overridden by a file with the same
+// name in EMAIL_TEMPLATES_DIR
+//
+//go:embed emails
+var Emails embed.FS
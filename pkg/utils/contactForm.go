@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type ContactFieldType string
+
+const (
+	TEXT_FIELD     ContactFieldType = "TEXT"
+	TEXTAREA_FIELD ContactFieldType = "TEXTAREA"
+	SELECT_FIELD   ContactFieldType = "SELECT"
+	NUMBER_FIELD   ContactFieldType = "NUMBER"
+	EMAIL_FIELD    ContactFieldType = "EMAIL"
+	URL_FIELD      ContactFieldType = "URL"
+)
+
+const defaultContactFieldMaxLength = 500
+
+// ContactFormSchema lists the extra fields the contact form accepts, on top of name, email & message
+type ContactFormSchema struct {
+	Fields []ContactFormField `json:"fields"`
+}
+
+type ContactFormField struct {
+	Name     string           `json:"name"`
+	Label    string           `json:"label"`
+	Type     ContactFieldType `json:"type"`
+	Required bool             `json:"required"`
+	Options  []string         `json:"options,omitempty"` // SELECT only
+	// MaxLength defaults to 500 characters
+	MaxLength int `json:"maxLength,omitempty"`
+}
+
+// ContactField is a submitted extra field, in schema order, for the email templates
+type ContactField struct {
+	Label string
+	Value string
+}
+
+var (
+	contactFieldNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,63}$`)
+	reservedContactFields   = map[string]bool{"name": true, "email": true, "message": true, "captcha": true, "recaptcha": true, "formToken": true, "website": true, "fields": true}
+)
+
+// Validate checks the schema itself, before it is saved
+func (schema ContactFormSchema) Validate() error {
+	seen := make(map[string]bool)
+
+	for _, field := range schema.Fields {
+		if !contactFieldNamePattern.MatchString(field.Name) {
+			return fmt.Errorf("field name %q must start with a letter and only contain letters, numbers and underscores", field.Name)
+		}
+
+		if reservedContactFields[field.Name] {
+			return fmt.Errorf("field name %q is reserved", field.Name)
+		}
+
+		if seen[field.Name] {
+			return fmt.Errorf("field %q is defined more than once", field.Name)
+		}
+		seen[field.Name] = true
+
+		if field.Label == "" {
+			return fmt.Errorf("field %q needs a label", field.Name)
+		}
+
+		if field.MaxLength < 0 {
+			return fmt.Errorf("field %q has a negative max length", field.Name)
+		}
+
+		switch field.Type {
+		case TEXT_FIELD, TEXTAREA_FIELD, NUMBER_FIELD, EMAIL_FIELD, URL_FIELD:
+			if len(field.Options) > 0 {
+				return fmt.Errorf("field %q has options but is not a SELECT field", field.Name)
+			}
+		case SELECT_FIELD:
+			if len(field.Options) == 0 {
+				return fmt.Errorf("select field %q needs at least one option", field.Name)
+			}
+		default:
+			return fmt.Errorf("field %q has unknown type %q", field.Name, field.Type)
+		}
+	}
+
+	return nil
+}
+
+// ValidateFields checks submitted values against the schema, returning them as strings
+func (schema ContactFormSchema) ValidateFields(submitted map[string]json.RawMessage) (map[string]string, error) {
+	known := make(map[string]bool, len(schema.Fields))
+	values := make(map[string]string, len(schema.Fields))
+
+	for _, field := range schema.Fields {
+		known[field.Name] = true
+
+		value, err := contactFieldValue(submitted[field.Name])
+		if err != nil {
+			return nil, fmt.Errorf("%s %v", field.Label, err)
+		}
+
+		if value == "" {
+			if field.Required {
+				return nil, fmt.Errorf("%s is required", field.Label)
+			}
+			continue
+		}
+
+		if err := field.validate(value); err != nil {
+			return nil, fmt.Errorf("%s %v", field.Label, err)
+		}
+
+		values[field.Name] = value
+	}
+
+	for name := range submitted {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+	}
+
+	return values, nil
+}
+
+// Labelled returns the stored values in schema order with their labels, fields removed
+// from the schema since the message was sent are listed last under their name
+func (schema ContactFormSchema) Labelled(values map[string]string) []ContactField {
+	fields := make([]ContactField, 0, len(values))
+	listed := make(map[string]bool, len(values))
+
+	for _, field := range schema.Fields {
+		if value, exists := values[field.Name]; exists {
+			fields = append(fields, ContactField{Label: field.Label, Value: value})
+			listed[field.Name] = true
+		}
+	}
+
+	for name, value := range values {
+		if !listed[name] {
+			fields = append(fields, ContactField{Label: name, Value: value})
+		}
+	}
+
+	return fields
+}
+
+func (field ContactFormField) validate(value string) error {
+	maxLength := field.MaxLength
+	if maxLength == 0 {
+		maxLength = defaultContactFieldMaxLength
+	}
+
+	if len([]rune(value)) > maxLength {
+		return fmt.Errorf("must be at most %d characters", maxLength)
+	}
+
+	switch field.Type {
+	case SELECT_FIELD:
+		for _, option := range field.Options {
+			if value == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(field.Options, ", "))
+	case NUMBER_FIELD:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("must be a number")
+		}
+	case EMAIL_FIELD:
+		if err := ValidateEmailAddress(value); err != nil {
+			return err
+		}
+	case URL_FIELD:
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("must be an http or https URL")
+		}
+	case TEXT_FIELD:
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("must be a single line")
+		}
+	}
+
+	return nil
+}
+
+// contactFieldValue accepts strings and numbers, so number fields can be sent either way
+func contactFieldValue(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text), nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number.String(), nil
+	}
+
+	return "", fmt.Errorf("must be a string or number")
+}
@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/mailer"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	emailRetryBaseDelay = 30 * time.Second
+	emailRetryMaxDelay  = time.Hour
+	// emails stuck in SENDING for longer than this (e.g. the worker crashed) are retried
+	emailSendingTimeout = 10 * time.Minute
+	emailWorkerBatch    = 10
+)
+
+var emailWorkerWake = make(chan struct{}, 1)
+
+// QueueTemplatedEmail renders the named email template into the message and queues it
+func QueueTemplatedEmail(message mailer.Message, templateName string, data interface{}) (*structs.OutboundEmails, error) {
+	content, err := RenderEmail(templateName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Content = *content
+	return QueueEmail(message)
+}
+
+// QueueEmail stores an email in the outbox, the background worker sends it
+func QueueEmail(message mailer.Message) (*structs.OutboundEmails, error) {
+	email := structs.OutboundEmails{
+		Recipient:     message.To,
+		ReplyTo:       message.ReplyTo,
+		Subject:       message.Subject,
+		HTML:          message.HTML,
+		Text:          message.Text,
+		Status:        structs.EMAIL_PENDING,
+		MaxAttempts:   getEnvInt("EMAIL_MAX_ATTEMPTS", 5),
+		NextAttemptAt: time.Now(),
+	}
+
+	for _, attachment := range message.Attachments {
+		email.Attachments = append(email.Attachments, structs.OutboundEmailAttachments{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Data:        attachment.Data,
+		})
+	}
+
+	if err := initializers.DB.Create(&email).Error; err != nil {
+		return nil, err
+	}
+
+	wakeEmailWorker()
+	return &email, nil
+}
+
+// RequeueEmail resets a (dead) email so the worker sends it again
+func RequeueEmail(email *structs.OutboundEmails) error {
+	err := initializers.DB.Model(email).Updates(map[string]interface{}{
+		"status":          structs.EMAIL_PENDING,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	wakeEmailWorker()
+	return nil
+}
+
+// StartEmailWorker sends queued emails in the background, retrying failures with exponential
+// backoff until EMAIL_MAX_ATTEMPTS is reached and the email is dead-lettered
+func StartEmailWorker() {
+	interval := time.Duration(getEnvInt("EMAIL_WORKER_INTERVAL", 10)) * time.Second
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			processEmailOutbox()
+
+			select {
+			case <-ticker.C:
+			case <-emailWorkerWake:
+			}
+		}
+	}()
+
+	log.Info("Email worker started")
+}
+
+func processEmailOutbox() {
+	// Recover emails left in SENDING by a worker that stopped mid-send
+	initializers.DB.Model(&structs.OutboundEmails{}).
+		Where("status = ? AND updated_at < ?", structs.EMAIL_SENDING, time.Now().Add(-emailSendingTimeout)).
+		Update("status", structs.EMAIL_PENDING)
+
+	for {
+		var emails []structs.OutboundEmails
+		err := initializers.DB.
+			Where("status = ? AND next_attempt_at <= ?", structs.EMAIL_PENDING, time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(emailWorkerBatch).
+			Find(&emails).Error
+		if err != nil {
+			log.Error("Error reading email outbox: ", err)
+			return
+		}
+
+		for i := range emails {
+			if err := initializers.DB.Model(&emails[i]).Association("Attachments").Find(&emails[i].Attachments); err != nil {
+				log.Error("Error reading email attachments: ", err)
+				continue
+			}
+
+			sendOutboundEmail(&emails[i])
+		}
+
+		if len(emails) < emailWorkerBatch {
+			return
+		}
+	}
+}
+
+func sendOutboundEmail(email *structs.OutboundEmails) {
+	// Claim the email, so other replicas running the worker skip it
+	result := initializers.DB.Model(&structs.OutboundEmails{}).
+		Where("id = ? AND status = ?", email.ID, structs.EMAIL_PENDING).
+		Update("status", structs.EMAIL_SENDING)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return
+	}
+
+	sendErr := initializers.Mailer.Send(outboundEmailMessage(email))
+
+	email.Attempts++
+	attempt := structs.OutboundEmailAttempts{
+		EmailId:   email.ID,
+		Attempt:   email.Attempts,
+		Succeeded: sendErr == nil,
+	}
+
+	updates := map[string]interface{}{"attempts": email.Attempts}
+
+	if sendErr == nil {
+		now := time.Now()
+		updates["status"] = structs.EMAIL_SENT
+		updates["sent_at"] = &now
+		updates["last_error"] = ""
+	} else {
+		attempt.Error = sendErr.Error()
+		updates["last_error"] = sendErr.Error()
+
+		if email.Attempts >= email.MaxAttempts {
+			updates["status"] = structs.EMAIL_DEAD
+			log.Errorf("Email %d to %s dead-lettered after %d attempts: %v", email.ID, email.Recipient, email.Attempts, sendErr)
+		} else {
+			updates["status"] = structs.EMAIL_PENDING
+			updates["next_attempt_at"] = time.Now().Add(retryDelay(email.Attempts, emailRetryBaseDelay, emailRetryMaxDelay))
+			log.Warnf("Email %d to %s failed (attempt %d), retrying: %v", email.ID, email.Recipient, email.Attempts, sendErr)
+		}
+	}
+
+	if err := initializers.DB.Create(&attempt).Error; err != nil {
+		log.Error("Error recording email attempt: ", err)
+	}
+
+	if err := initializers.DB.Model(email).Updates(updates).Error; err != nil {
+		log.Error("Error updating outbound email: ", err)
+	}
+}
+
+func outboundEmailMessage(email *structs.OutboundEmails) mailer.Message {
+	message := mailer.Message{
+		To:      email.Recipient,
+		ReplyTo: email.ReplyTo,
+		Content: mailer.Content{Subject: email.Subject, HTML: email.HTML, Text: email.Text},
+	}
+
+	for _, attachment := range email.Attachments {
+		message.Attachments = append(message.Attachments, mailer.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Data:        attachment.Data,
+		})
+	}
+
+	return message
+}
+
+// retryDelay doubles the base delay for every failed attempt, up to maxDelay
+func retryDelay(attempts int, baseDelay time.Duration, maxDelay time.Duration) time.Duration {
+	delay := time.Duration(float64(baseDelay) * math.Pow(2, float64(attempts-1)))
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+func wakeEmailWorker() {
+	select {
+	case emailWorkerWake <- struct{}{}:
+	default:
+	}
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/mailer"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/templates"
+)
+
+type ContactEmailData struct {
+	Name    string
+	Email   string
+	Message string
+	Fields  []ContactField
+	// Subject & Body replace the default receipt text when the auto-reply is customised
+	Subject string
+	Body    string
+}
+
+type ContactReplyEmailData struct {
+	Name    string
+	Message string
+	SentAt  time.Time
+	Body    string
+}
+
+type TokenLinkEmailData struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+const (
+	CONTACT_RECEIPT_EMAIL      = "contact_receipt"
+	CONTACT_NOTIFICATION_EMAIL = "contact_notification"
+	CONTACT_REPLY_EMAIL        = "contact_reply"
+	VERIFICATION_EMAIL         = "verification"
+	PASSWORD_RESET_EMAIL       = "password_reset"
+)
+
+var ErrUnknownEmailTemplate = errors.New("unknown email template")
+
+// emailTemplateSamples holds the sample data each template is previewed with
+var emailTemplateSamples = map[string]interface{}{
+	CONTACT_RECEIPT_EMAIL: ContactEmailData{
+		Name: "Jane Doe", Email: "jane@example.com", Message: "Hi Jack,\nI'd love to chat about a <b>project</b>.",
+	},
+	CONTACT_NOTIFICATION_EMAIL: ContactEmailData{
+		Name: "Jane Doe", Email: "jane@example.com", Message: "Hi Jack,\nI'd love to chat about a <b>project</b>.",
+		Fields: []ContactField{{Label: "Company", Value: "Acme Ltd"}, {Label: "Budget", Value: "£5k - £10k"}},
+	},
+	CONTACT_REPLY_EMAIL: ContactReplyEmailData{
+		Name: "Jane Doe", Message: "Hi Jack,\nI'd love to chat about a project.", SentAt: time.Now().Add(-24 * time.Hour), Body: "Hi Jane,\nThanks for getting in touch!",
+	},
+	VERIFICATION_EMAIL: TokenLinkEmailData{
+		URL: "https://example.com/verify?token=sample", ExpiresAt: time.Now().Add(24 * time.Hour),
+	},
+	PASSWORD_RESET_EMAIL: TokenLinkEmailData{
+		URL: "https://example.com/reset-password?token=sample", ExpiresAt: time.Now().Add(time.Hour),
+	},
+}
+
+type emailSiteData struct {
+	Name string
+	URL  string
+	Year int
+}
+
+type emailTemplateData struct {
+	Subject string
+	Site    emailSiteData
+	Data    interface{}
+}
+
+// RenderEmail renders the named template with the shared layout. The HTML part is auto-escaped,
+// the subject and plain text part are rendered from the .txt template.
+func RenderEmail(name string, data interface{}) (*mailer.Content, error) {
+	if _, exists := emailTemplateSamples[name]; !exists {
+		return nil, ErrUnknownEmailTemplate
+	}
+
+	templateData := emailTemplateData{
+		Site: emailSiteData{
+			Name: os.Getenv("EMAIL_FROM_PREFIX"),
+			URL:  os.Getenv("SITE_URL"),
+			Year: time.Now().Year(),
+		},
+		Data: data,
+	}
+
+	// Subject & plain text
+	textSource, err := readEmailTemplates("layout.txt", name+".txt")
+	if err != nil {
+		return nil, err
+	}
+
+	textTemplates, err := textTemplate.New(name).Parse(textSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s text template: %v", name, err)
+	}
+
+	var subject, text bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&subject, "subject", templateData); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %v", name, err)
+	}
+	templateData.Subject = strings.TrimSpace(subject.String())
+
+	if err := textTemplates.ExecuteTemplate(&text, "layout", templateData); err != nil {
+		return nil, fmt.Errorf("failed to render %s text: %v", name, err)
+	}
+
+	// HTML
+	htmlSource, err := readEmailTemplates("layout.html", name+".html")
+	if err != nil {
+		return nil, err
+	}
+
+	htmlTemplates, err := htmlTemplate.New(name).Parse(htmlSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s HTML template: %v", name, err)
+	}
+
+	var html bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&html, "layout", templateData); err != nil {
+		return nil, fmt.Errorf("failed to render %s HTML: %v", name, err)
+	}
+
+	return &mailer.Content{
+		Subject: templateData.Subject,
+		HTML:    html.String(),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}, nil
+}
+
+// PreviewEmail renders the named template with its sample data
+func PreviewEmail(name string) (*mailer.Content, error) {
+	sample, exists := emailTemplateSamples[name]
+	if !exists {
+		return nil, ErrUnknownEmailTemplate
+	}
+
+	return RenderEmail(name, sample)
+}
+
+func EmailTemplateNames() []string {
+	names := make([]string, 0, len(emailTemplateSamples))
+	for name := range emailTemplateSamples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readEmailTemplates concatenates the template files, preferring overrides in EMAIL_TEMPLATES_DIR
+func readEmailTemplates(fileNames ...string) (string, error) {
+	overrideDir := os.Getenv("EMAIL_TEMPLATES_DIR")
+
+	var source strings.Builder
+	for _, fileName := range fileNames {
+		if overrideDir != "" {
+			content, err := os.ReadFile(filepath.Join(overrideDir, fileName))
+			if err == nil {
+				source.Write(content)
+				continue
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return "", fmt.Errorf("failed to read email template %s: %v", fileName, err)
+			}
+		}
+
+		content, err := fs.ReadFile(templates.Emails, "emails/"+fileName)
+		if err != nil {
+			return "", fmt.Errorf("failed to read email template %s: %v", fileName, err)
+		}
+		source.Write(content)
+	}
+
+	return source.String(), nil
+}
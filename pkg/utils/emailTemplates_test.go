@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/mailer"
+	"github.com/google/uuid"
+)
+
+type capturedEmail struct {
+	mailer.CapturedMessage
+	Text string
+	HTML string
+}
+
+// sendThroughDevMailer renders the template into the message, sends it through a DevMailer
+// and reads the captured message back with its decoded text & HTML parts
+func sendThroughDevMailer(t *testing.T, message mailer.Message, templateName string, data interface{}) capturedEmail {
+	t.Helper()
+	t.Setenv("EMAIL_TEMPLATES_DIR", "")
+	t.Setenv("EMAIL_FROM_PREFIX", "Portfolio")
+
+	content, err := RenderEmail(templateName, data)
+	if err != nil {
+		t.Fatalf("RenderEmail(%s): %v", templateName, err)
+	}
+	message.Content = *content
+
+	devMailer, err := mailer.NewDevMailer(t.TempDir(), &mail.Address{Name: "Portfolio", Address: "noreply@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := devMailer.Send(message); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	messages, err := devMailer.List()
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("List() = %d messages, %v; want 1", len(messages), err)
+	}
+
+	raw, err := devMailer.Raw(messages[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	captured := capturedEmail{CapturedMessage: messages[0]}
+	_, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type")); mediaType {
+		case "text/plain":
+			captured.Text = string(body)
+		case "text/html":
+			captured.HTML = string(body)
+		}
+	}
+
+	return captured
+}
+
+func TestContactNotificationEmail(t *testing.T) {
+	data := ContactEmailData{
+		Name:    "Jane <b>Doe</b>",
+		Email:   "jane@example.com",
+		Message: "Hi,\n<script>alert(1)</script>",
+		Fields:  []ContactField{{Label: "Company", Value: "Acme & Co"}},
+	}
+
+	email := sendThroughDevMailer(t, mailer.Message{To: "owner@example.com", ReplyTo: data.Email}, CONTACT_NOTIFICATION_EMAIL, data)
+
+	if email.Subject != "Portfolio Contact Form - New Message" {
+		t.Errorf("Subject = %q", email.Subject)
+	}
+	if email.To != "<owner@example.com>" || email.ReplyTo != "<jane@example.com>" {
+		t.Errorf("To = %q, ReplyTo = %q; want the owner, replying to the visitor", email.To, email.ReplyTo)
+	}
+
+	if strings.Contains(email.HTML, "<script>") || strings.Contains(email.HTML, "<b>Doe</b>") {
+		t.Errorf("HTML part contains the visitor's markup unescaped:\n%s", email.HTML)
+	}
+	if !strings.Contains(email.HTML, "&lt;script&gt;") || !strings.Contains(email.HTML, "Acme &amp; Co") {
+		t.Errorf("HTML part is missing the escaped message or fields:\n%s", email.HTML)
+	}
+
+	for _, want := range []string{"Name: Jane <b>Doe</b>", "Company: Acme & Co", "<script>alert(1)</script>"} {
+		if !strings.Contains(email.Text, want) {
+			t.Errorf("Text part is missing %q:\n%s", want, email.Text)
+		}
+	}
+}
+
+func TestContactReceiptEmail(t *testing.T) {
+	data := ContactEmailData{Name: "Jane", Email: "jane@example.com", Message: "Hello there"}
+
+	email := sendThroughDevMailer(t, mailer.Message{To: data.Email}, CONTACT_RECEIPT_EMAIL, data)
+
+	if email.To != "<jane@example.com>" || email.ReplyTo != "" {
+		t.Errorf("To = %q, ReplyTo = %q; want the visitor with no Reply-To", email.To, email.ReplyTo)
+	}
+	if email.Subject == "" {
+		t.Error("Subject is empty")
+	}
+	if !strings.Contains(email.Text, "Hello there") || !strings.Contains(email.HTML, "Hello there") {
+		t.Errorf("receipt is missing the visitor's message:\ntext: %s\nhtml: %s", email.Text, email.HTML)
+	}
+}
+
+func TestTokenLinkEmails(t *testing.T) {
+	tests := []struct {
+		name         string
+		templateName string
+		path         string
+		subject      string
+	}{
+		{"verification", VERIFICATION_EMAIL, "/verify-email", "Verify your email address"},
+		{"password reset", PASSWORD_RESET_EMAIL, "/reset-password", "Reset your password"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			link := "https://example.com" + test.path + "?token=" + uuid.NewString()
+			data := TokenLinkEmailData{URL: link, ExpiresAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)}
+
+			email := sendThroughDevMailer(t, mailer.Message{To: "user@example.com"}, test.templateName, data)
+
+			if email.To != "<user@example.com>" {
+				t.Errorf("To = %q", email.To)
+			}
+			if email.Subject != test.subject {
+				t.Errorf("Subject = %q, want %q", email.Subject, test.subject)
+			}
+			if !strings.Contains(email.Text, link) || !strings.Contains(email.Text, "1 Mar 2024 12:30 UTC") {
+				t.Errorf("Text part is missing the link or expiry:\n%s", email.Text)
+			}
+			if !strings.Contains(email.HTML, `href="`+link+`"`) {
+				t.Errorf("HTML part is missing a link to %s:\n%s", link, email.HTML)
+			}
+		})
+	}
+}
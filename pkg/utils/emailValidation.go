@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+var ErrInvalidEmailAddress = errors.New("is not a valid email address")
+
+// ValidateEmailAddress accepts a bare RFC 5322 address (no display name) whose domain looks
+// deliverable, i.e. has at least one dot and valid DNS labels
+func ValidateEmailAddress(email string) error {
+	if len(email) > 254 {
+		return ErrInvalidEmailAddress
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || address.Name != "" {
+		return ErrInvalidEmailAddress
+	}
+
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], email[at+1:]
+	if len(local) > 64 || !strings.Contains(domain, ".") {
+		return ErrInvalidEmailAddress
+	}
+
+	for _, label := range strings.Split(domain, ".") {
+		if !validDomainLabel(label) {
+			return ErrInvalidEmailAddress
+		}
+	}
+
+	return nil
+}
+
+// validDomainLabel accepts letters (including internationalised), digits and inner hyphens
+func validDomainLabel(label string) bool {
+	if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return false
+	}
+
+	for _, r := range label {
+		if r == '-' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r > 127 {
+			continue
+		}
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+)
+
+const (
+	CONTRIBUTIONS_CACHE       = "contributions"
+	CONTRIBUTION_STATS_CACHE  = "stats"
+	PINNED_REPOSITORIES_CACHE = "pinned"
+	LANGUAGES_CACHE           = "languages"
+	ACTIVITY_CACHE            = "activity"
+)
+
+// GitHubCacheKey builds the GitHubCache key for a kind of GitHub data about a user, e.g. github:contributions:jake4-cx:2024
+func GitHubCacheKey(kind string, login string, suffix ...string) string {
+	return strings.Join(append([]string{"github", kind, strings.ToLower(login)}, suffix...), ":")
+}
+
+// RepositoryActivityCacheKey is the key of a repository's recent activity, shared by every project linking it
+func RepositoryActivityCacheKey(owner string, name string) string {
+	return GitHubCacheKey(ACTIVITY_CACHE, owner, strings.ToLower(name))
+}
+
+// MergedContributionsCacheKey is the key of GET /contributions for the GitHub user (empty when there's no default
+// user) merged with the configured accounts, for the year (0 for the last 12 months)
+func MergedContributionsCacheKey(githubLogin string, year int) string {
+	parts := []string{"contributions", "merged", "github/" + strings.ToLower(githubLogin)}
+	for _, account := range initializers.ContributionAccounts {
+		parts = append(parts, account.Provider.Name()+"/"+strings.ToLower(account.Login))
+	}
+	return strings.Join(append(parts, strconv.Itoa(year)), ":")
+}
+
+// InvalidateGitHubUserCache drops the user's cached data that new activity changes: the last 12 months and
+// this year's contributions (including the merged calendar), stats and languages
+func InvalidateGitHubUserCache(login string) error {
+	return initializers.GitHubCache.Invalidate(
+		GitHubCacheKey(CONTRIBUTIONS_CACHE, login, "0"),
+		GitHubCacheKey(CONTRIBUTIONS_CACHE, login, strconv.Itoa(time.Now().Year())),
+		GitHubCacheKey(CONTRIBUTION_STATS_CACHE, login),
+		GitHubCacheKey(LANGUAGES_CACHE, login),
+		GitHubCacheKey(PINNED_REPOSITORIES_CACHE, login),
+		MergedContributionsCacheKey(login, 0),
+		MergedContributionsCacheKey(login, time.Now().Year()),
+	)
+}
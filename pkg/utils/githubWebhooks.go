@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	log "github.com/sirupsen/logrus"
+)
+
+const webhookPruneInterval = time.Hour
+
+// StartWebhookPruneWorker deletes stored webhook deliveries older than GITHUB_WEBHOOK_RETENTION_DAYS
+func StartWebhookPruneWorker() {
+	retention := time.Duration(getEnvInt("GITHUB_WEBHOOK_RETENTION_DAYS", 30)) * 24 * time.Hour
+
+	go func() {
+		ticker := time.NewTicker(webhookPruneInterval)
+		defer ticker.Stop()
+
+		for {
+			pruneWebhookDeliveries(retention)
+			<-ticker.C
+		}
+	}()
+
+	log.Info("Webhook prune worker started")
+}
+
+func pruneWebhookDeliveries(retention time.Duration) {
+	result := initializers.DB.Unscoped().
+		Where("created_at < ?", time.Now().Add(-retention)).
+		Delete(&structs.GitHubWebhookDeliveries{})
+	if result.Error != nil {
+		log.Error("Error pruning webhook deliveries: ", result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Info("Pruned ", result.RowsAffected, " webhook deliveries")
+	}
+}
+
+// githubWebhookPayload holds the fields of push, release, star and repository payloads we react to
+type githubWebhookPayload struct {
+	Action     string `json:"action"`
+	Ref        string `json:"ref"`
+	Repository *struct {
+		Name          string `json:"name"`
+		FullName      string `json:"full_name"`
+		HTMLURL       string `json:"html_url"`
+		DefaultBranch string `json:"default_branch"`
+		Owner         struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+	// Changes is set on repository renamed and transferred events
+	Changes struct {
+		Repository struct {
+			Name struct {
+				From string `json:"from"`
+			} `json:"name"`
+		} `json:"repository"`
+		Owner struct {
+			From struct {
+				User struct {
+					Login string `json:"login"`
+				} `json:"user"`
+				Organization struct {
+					Login string `json:"login"`
+				} `json:"organization"`
+			} `json:"from"`
+		} `json:"owner"`
+	} `json:"changes"`
+}
+
+// ProcessGitHubWebhook reacts to a verified delivery and saves its outcome:
+//   - push to the default branch invalidates the pusher's and owner's cached contributions, and the repository's activity
+//   - release invalidates the repository's activity
+//   - push, release, star and repository events re-sync the projects linked to the repository,
+//     following renames and transfers by updating the projects' GitHub URLs
+func ProcessGitHubWebhook(delivery *structs.GitHubWebhookDeliveries) error {
+	var payload githubWebhookPayload
+	if err := json.Unmarshal([]byte(delivery.Payload), &payload); err != nil {
+		return finishWebhookDelivery(delivery, err)
+	}
+
+	delivery.Action = payload.Action
+	delivery.Sender = payload.Sender.Login
+	delivery.Reactions = []string{}
+
+	// Every event we react to is about a repository (ping from an organisation hook has none)
+	if payload.Repository == nil {
+		return finishWebhookDelivery(delivery, nil)
+	}
+	repository := payload.Repository
+	delivery.Repository = repository.FullName
+
+	switch delivery.Event {
+	case "push":
+		if payload.Ref != "refs/heads/"+repository.DefaultBranch {
+			return finishWebhookDelivery(delivery, nil)
+		}
+
+		logins := []string{repository.Owner.Login}
+		if payload.Sender.Login != "" && !strings.EqualFold(payload.Sender.Login, repository.Owner.Login) {
+			logins = append(logins, payload.Sender.Login)
+		}
+		for _, login := range logins {
+			if err := InvalidateGitHubUserCache(login); err != nil {
+				return finishWebhookDelivery(delivery, err)
+			}
+			delivery.Reactions = append(delivery.Reactions, "invalidated cache for "+login)
+		}
+
+		if err := invalidateRepositoryActivity(delivery, repository.FullName); err != nil {
+			return finishWebhookDelivery(delivery, err)
+		}
+
+	case "release":
+		if err := invalidateRepositoryActivity(delivery, repository.FullName); err != nil {
+			return finishWebhookDelivery(delivery, err)
+		}
+
+	case "star":
+		// Only the project sync below
+
+	case "repository":
+		oldOwner, oldName := repository.Owner.Login, repository.Name
+		switch payload.Action {
+		case "renamed":
+			oldName = payload.Changes.Repository.Name.From
+		case "transferred":
+			oldOwner = payload.Changes.Owner.From.User.Login
+			if oldOwner == "" {
+				oldOwner = payload.Changes.Owner.From.Organization.Login
+			}
+		}
+
+		if !strings.EqualFold(oldOwner+"/"+oldName, repository.FullName) {
+			moved, err := relinkProjectRepositories(oldOwner, oldName, repository.HTMLURL)
+			if err != nil {
+				return finishWebhookDelivery(delivery, err)
+			}
+			for _, projectID := range moved {
+				delivery.Reactions = append(delivery.Reactions, "updated GitHub URL of project "+strconv.FormatUint(uint64(projectID), 10))
+			}
+		}
+
+	default:
+		return finishWebhookDelivery(delivery, nil)
+	}
+
+	owner, name, _ := strings.Cut(repository.FullName, "/")
+	projectIDs, err := FindProjectsByRepository(owner, name)
+	if err != nil {
+		return finishWebhookDelivery(delivery, err)
+	}
+
+	for _, projectID := range projectIDs {
+		QueueRepositorySync(projectID)
+		delivery.Reactions = append(delivery.Reactions, "queued sync of project "+strconv.FormatUint(uint64(projectID), 10))
+	}
+
+	return finishWebhookDelivery(delivery, nil)
+}
+
+func finishWebhookDelivery(delivery *structs.GitHubWebhookDeliveries, processErr error) error {
+	switch {
+	case processErr != nil:
+		delivery.Status = structs.WEBHOOK_FAILED
+		delivery.Error = processErr.Error()
+	case len(delivery.Reactions) == 0:
+		delivery.Status = structs.WEBHOOK_IGNORED
+		delivery.Error = ""
+	default:
+		delivery.Status = structs.WEBHOOK_PROCESSED
+		delivery.Error = ""
+	}
+
+	if err := initializers.DB.Save(delivery).Error; err != nil {
+		log.Error("Error saving webhook delivery: ", err)
+		if processErr == nil {
+			return err
+		}
+	}
+
+	return processErr
+}
+
+func invalidateRepositoryActivity(delivery *structs.GitHubWebhookDeliveries, fullName string) error {
+	owner, name, _ := strings.Cut(fullName, "/")
+	if err := initializers.GitHubCache.Invalidate(RepositoryActivityCacheKey(owner, name)); err != nil {
+		return err
+	}
+
+	delivery.Reactions = append(delivery.Reactions, "invalidated activity for "+fullName)
+	return nil
+}
+
+// FindProjectsByRepository returns the projects whose GitHub URL points at owner/name
+func FindProjectsByRepository(owner string, name string) ([]uint, error) {
+	var urls []structs.ProjectURLs
+	if err := initializers.DB.Where("git_hub_url <> ''").Find(&urls).Error; err != nil {
+		return nil, err
+	}
+
+	var projectIDs []uint
+	for _, url := range urls {
+		urlOwner, urlName, err := github.ParseRepositoryURL(url.GitHubURL)
+		if err == nil && strings.EqualFold(urlOwner, owner) && strings.EqualFold(urlName, name) {
+			projectIDs = append(projectIDs, url.ProjectId)
+		}
+	}
+
+	return projectIDs, nil
+}
+
+// relinkProjectRepositories points projects linked to a renamed or transferred repository at its new URL
+func relinkProjectRepositories(oldOwner string, oldName string, newURL string) ([]uint, error) {
+	projectIDs, err := FindProjectsByRepository(oldOwner, oldName)
+	if err != nil || len(projectIDs) == 0 {
+		return nil, err
+	}
+
+	err = initializers.DB.Model(&structs.ProjectURLs{}).Where("project_id IN ?", projectIDs).Update("git_hub_url", newURL).Error
+	return projectIDs, err
+}
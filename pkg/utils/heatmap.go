@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/contributions"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+)
+
+type HeatmapTheme struct {
+	Background string
+	Text       string
+	// Palette holds the cell colours from no contributions up to the fourth quartile
+	Palette [5]string
+}
+
+var HeatmapThemes = map[string]HeatmapTheme{
+	"light": {
+		Background: "#ffffff",
+		Text:       "#57606a",
+		Palette:    contributions.Colors,
+	},
+	"dark": {
+		Background: "#0d1117",
+		Text:       "#8b949e",
+		Palette:    [5]string{"#161b22", "#0e4429", "#006d32", "#26a641", "#39d353"},
+	},
+}
+
+type HeatmapOptions struct {
+	Theme HeatmapTheme
+	// UseDayColors renders each cell with the colour GitHub returned for the day instead of the theme palette
+	UseDayColors bool
+	// CellSize is the width of each day in pixels
+	CellSize int
+	// Caption is shown under the graph, e.g. "1,234 contributions in 2024"
+	Caption string
+}
+
+const (
+	MIN_HEATMAP_CELL_SIZE     = 6
+	MAX_HEATMAP_CELL_SIZE     = 24
+	DEFAULT_HEATMAP_CELL_SIZE = 10
+)
+
+var hexColor = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ParseHeatmapPalette parses five comma separated hex colours, with or without the leading #
+func ParseHeatmapPalette(value string) ([5]string, error) {
+	var palette [5]string
+
+	colors := strings.Split(value, ",")
+	if len(colors) != len(palette) {
+		return palette, fmt.Errorf("palette must have %d colours", len(palette))
+	}
+
+	for i, color := range colors {
+		color = strings.TrimSpace(color)
+		if !hexColor.MatchString(color) {
+			return palette, fmt.Errorf("invalid colour %q", color)
+		}
+		palette[i] = "#" + strings.TrimPrefix(color, "#")
+	}
+
+	return palette, nil
+}
+
+var heatmapLevels = map[string]int{
+	"NONE":            0,
+	"FIRST_QUARTILE":  1,
+	"SECOND_QUARTILE": 2,
+	"THIRD_QUARTILE":  3,
+	"FOURTH_QUARTILE": 4,
+}
+
+// RenderHeatmap draws the contribution calendar as an SVG, one column per week
+func RenderHeatmap(calendar github.ContributionCalendar, options HeatmapOptions) []byte {
+	cell := options.CellSize
+	gap := cell / 5
+	if gap < 1 {
+		gap = 1
+	}
+	step := cell + gap
+	fontSize := cell
+	if fontSize < 9 {
+		fontSize = 9
+	}
+
+	left := fontSize * 3
+	top := fontSize + gap*2
+	width := left + len(calendar.Weeks)*step + gap
+	height := top + 7*step + fontSize + gap*3
+
+	var svg bytes.Buffer
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`,
+		width, height, width, height, html.EscapeString(options.Caption))
+	fmt.Fprintf(&svg, `<title>%s</title>`, html.EscapeString(options.Caption))
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`, options.Theme.Background)
+	fmt.Fprintf(&svg, `<g font-family="-apple-system,BlinkMacSystemFont,'Segoe UI',Helvetica,Arial,sans-serif" font-size="%d" fill="%s">`,
+		fontSize, options.Theme.Text)
+
+	for _, weekday := range []int{1, 3, 5} {
+		fmt.Fprintf(&svg, `<text x="0" y="%d">%s</text>`, top+weekday*step+cell-1, time.Weekday(weekday).String()[:3])
+	}
+
+	// Month labels sit above the first week starting in that month, skipping any that would overlap
+	lastLabel := -4
+	lastMonth := ""
+	for i, week := range calendar.Weeks {
+		if len(week.ContributionDays) == 0 || len(week.ContributionDays[0].Date) < 7 {
+			continue
+		}
+		month := week.ContributionDays[0].Date[:7]
+		if month != lastMonth && i-lastLabel >= 3 {
+			if date, err := time.Parse("2006-01", month); err == nil {
+				fmt.Fprintf(&svg, `<text x="%d" y="%d">%s</text>`, left+i*step, fontSize, date.Month().String()[:3])
+				lastLabel = i
+			}
+		}
+		lastMonth = month
+	}
+	svg.WriteString(`</g>`)
+
+	for i, week := range calendar.Weeks {
+		for _, day := range week.ContributionDays {
+			color := day.Color
+			if !options.UseDayColors || !hexColor.MatchString(color) {
+				color = options.Theme.Palette[dayLevel(day)]
+			}
+
+			fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s"><title>%s</title></rect>`,
+				left+i*step, top+day.Weekday*step, cell, cell, cell/5, color,
+				html.EscapeString(contributionLabel(day.ContributionCount)+" on "+day.Date))
+		}
+	}
+
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" font-family="-apple-system,BlinkMacSystemFont,'Segoe UI',Helvetica,Arial,sans-serif" font-size="%d" fill="%s">%s</text>`,
+		left, height-gap*2, fontSize, options.Theme.Text, html.EscapeString(options.Caption))
+	svg.WriteString(`</svg>`)
+
+	return svg.Bytes()
+}
+
+// dayLevel is the quartile GitHub assigned the day, or a rough one from the count for older cached calendars
+func dayLevel(day github.ContributionDay) int {
+	if level, ok := heatmapLevels[day.ContributionLevel]; ok {
+		return level
+	}
+
+	switch count := day.ContributionCount; {
+	case count == 0:
+		return 0
+	case count < 4:
+		return 1
+	case count < 8:
+		return 2
+	case count < 12:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func contributionLabel(count int) string {
+	if count == 1 {
+		return "1 contribution"
+	}
+	return FormatThousands(count) + " contributions"
+}
+
+// FormatThousands formats n with comma separators, e.g. 1234 as 1,234
+func FormatThousands(n int) string {
+	if n < 0 {
+		return "-" + FormatThousands(-n)
+	}
+
+	digits := strconv.Itoa(n)
+
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return digits
+}
@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/storage"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	log "github.com/sirupsen/logrus"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+)
+
+const mediaSyncInterval = time.Minute
+
+var (
+	mediaSyncMutex sync.Mutex
+	lastMediaSync  time.Time
+)
+
+// SyncMediaLibrary registers uploaded objects that are not in the media library yet and
+// removes assets whose objects no longer exist. Unless forced it runs at most once a minute.
+func SyncMediaLibrary(store storage.Storage, force bool) error {
+	mediaSyncMutex.Lock()
+	defer mediaSyncMutex.Unlock()
+
+	if !force && time.Since(lastMediaSync) < mediaSyncInterval {
+		return nil
+	}
+
+	objects, err := store.List(os.Getenv("BUCKET_ENDPOINT_URI") + "/")
+	if err != nil {
+		return err
+	}
+
+	var assets []structs.MediaAssets
+	if err := initializers.DB.Find(&assets).Error; err != nil {
+		return err
+	}
+
+	knownKeys := make(map[string]bool, len(assets))
+	for _, asset := range assets {
+		knownKeys[asset.ObjectKey] = true
+	}
+
+	existingKeys := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		existingKeys[object.Key] = true
+
+		if knownKeys[object.Key] {
+			continue
+		}
+
+		if _, err := RegisterMediaAsset(store, object.Key); err != nil {
+			log.Warn("Error registering media asset ", object.Key, ": ", err)
+		}
+	}
+
+	for _, asset := range assets {
+		if existingKeys[asset.ObjectKey] {
+			continue
+		}
+
+		if err := initializers.DB.Unscoped().Delete(&asset).Error; err != nil {
+			log.Error("Error removing missing media asset: ", err)
+		}
+	}
+
+	lastMediaSync = time.Now()
+	return nil
+}
+
+// RegisterMediaAsset records an uploaded object in the media library (or returns the existing record)
+func RegisterMediaAsset(store storage.Storage, key string) (*structs.MediaAssets, error) {
+	return registerMediaAsset(initializers.DB, store, key)
+}
+
+func registerMediaAsset(db *gorm.DB, store storage.Storage, key string) (*structs.MediaAssets, error) {
+	var asset structs.MediaAssets
+	if err := db.Where("object_key = ?", key).First(&asset).Error; err == nil {
+		return &asset, nil
+	}
+
+	contentHash, config, format, err := inspectMediaObject(store, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return createMediaAsset(db, store, key, contentHash, config, format)
+}
+
+// createMediaAsset stores the media library record of an object whose content was already inspected
+func createMediaAsset(db *gorm.DB, store storage.Storage, key string, contentHash string, config *image.Config, format string) (*structs.MediaAssets, error) {
+	objectInfo, err := store.Head(key)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := structs.MediaAssets{
+		ObjectKey:   key,
+		Category:    MediaCategoryFromKey(key),
+		Size:        objectInfo.Size,
+		ContentType: objectInfo.ContentType,
+		ContentHash: contentHash,
+		UploadedAt:  objectInfo.LastModified,
+	}
+
+	if config != nil {
+		asset.Width = config.Width
+		asset.Height = config.Height
+		if asset.ContentType == "" || asset.ContentType == "application/octet-stream" {
+			asset.ContentType = "image/" + format
+		}
+	}
+
+	if err := db.Create(&asset).Error; err != nil {
+		return nil, err
+	}
+
+	return &asset, nil
+}
+
+// inspectMediaObject reads the object once, hashing it and decoding the image dimensions when it is an image
+func inspectMediaObject(store storage.Storage, key string) (string, *image.Config, string, error) {
+	reader, err := store.Open(key)
+	if err != nil {
+		return "", nil, "", err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	teeReader := io.TeeReader(reader, hash)
+
+	var imageConfig *image.Config
+	config, format, err := image.DecodeConfig(teeReader)
+	if err == nil {
+		imageConfig = &config
+	}
+
+	// Hash whatever the image decoder did not read
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", nil, "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), imageConfig, format, nil
+}
+
+// MediaCategoryFromKey reads the upload category from keys shaped like "uploads/<category>/<id>"
+func MediaCategoryFromKey(key string) structs.UploadCategory {
+	segments := strings.Split(key, "/")
+	if len(segments) < 2 {
+		return ""
+	}
+
+	return structs.UploadCategory(segments[len(segments)-2])
+}
+
+// GetMediaUsage returns the projects and technologies referencing each of the given object keys
+func GetMediaUsage(keys []string) (map[string]structs.MediaUsageModel, error) {
+	usage := make(map[string]structs.MediaUsageModel, len(keys))
+	if len(keys) == 0 {
+		return usage, nil
+	}
+
+	var projectImages []structs.ProjectImages
+	if err := initializers.DB.Where("image_key IN ?", keys).Find(&projectImages).Error; err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]uint, 0, len(projectImages))
+	for _, projectImage := range projectImages {
+		projectIDs = append(projectIDs, projectImage.ProjectId)
+	}
+
+	projectNames := make(map[uint]string)
+	if len(projectIDs) > 0 {
+		var projects []structs.Projects
+		if err := initializers.DB.Select("id", "project_name").Where("id IN ?", projectIDs).Find(&projects).Error; err != nil {
+			return nil, err
+		}
+		for _, project := range projects {
+			projectNames[project.ID] = project.ProjectName
+		}
+	}
+
+	for _, projectImage := range projectImages {
+		projectName, exists := projectNames[projectImage.ProjectId]
+		if !exists {
+			continue // Image of a deleted project
+		}
+
+		entry := usage[projectImage.ImageKey]
+		entry.Projects = append(entry.Projects, structs.MediaUsageReference{ID: projectImage.ProjectId, Name: projectName})
+		usage[projectImage.ImageKey] = entry
+	}
+
+	var technologies []structs.Technologies
+	if err := initializers.DB.Where("technology_image_key IN ?", keys).Find(&technologies).Error; err != nil {
+		return nil, err
+	}
+
+	for _, technology := range technologies {
+		entry := usage[technology.TechnologyImageKey]
+		entry.Technologies = append(entry.Technologies, structs.MediaUsageReference{ID: technology.ID, Name: technology.TechnologyName})
+		usage[technology.TechnologyImageKey] = entry
+	}
+
+	return usage, nil
+}
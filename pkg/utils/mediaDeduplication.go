@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/storage"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ConfirmUpload registers a finished upload in the media library. If an asset with the same
+// content already exists the new object is removed and the existing asset is returned instead.
+func ConfirmUpload(store storage.Storage, key string) (*structs.MediaAssets, bool, error) {
+	asset, err := RegisterMediaAsset(store, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return deduplicateMediaAsset(store, asset)
+}
+
+// deduplicateMediaAsset merges the asset into an older asset with the same content, if there is one
+func deduplicateMediaAsset(store storage.Storage, asset *structs.MediaAssets) (*structs.MediaAssets, bool, error) {
+	canonical, err := findCanonicalMediaAsset(initializers.DB, asset.ContentHash)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if canonical == nil || canonical.ID == asset.ID {
+		return asset, false, nil
+	}
+
+	if err := MergeMediaAsset(store, canonical, asset); err != nil {
+		return nil, false, err
+	}
+
+	return canonical, true, nil
+}
+
+// RegisterMediaReferences registers the objects an entity was just saved with, inside the caller's transaction.
+// Duplicates of existing assets are merged, which points the saved references at the canonical asset. The
+// merged objects are returned for DeleteMediaObjects once the transaction has committed.
+func RegisterMediaReferences(tx *gorm.DB, store storage.Storage, keys ...string) ([]string, error) {
+	var mergedKeys []string
+	seen := make(map[string]bool, len(keys))
+
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		asset, err := registerMediaAsset(tx, store, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register media: %v", err)
+		}
+
+		canonical, err := findCanonicalMediaAsset(tx, asset.ContentHash)
+		if err != nil {
+			return nil, err
+		}
+
+		if canonical == nil || canonical.ID == asset.ID {
+			continue
+		}
+
+		if err := mergeMediaAssetRows(tx, canonical, asset); err != nil {
+			return nil, err
+		}
+		mergedKeys = append(mergedKeys, asset.ObjectKey)
+	}
+
+	return mergedKeys, nil
+}
+
+// DeleteMediaObjects removes the objects of merged duplicates. Their references are already rewritten,
+// so a leftover object only wastes space.
+func DeleteMediaObjects(store storage.Storage, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			log.Warn("Error deleting duplicate media object ", key, ": ", err)
+		}
+	}
+}
+
+// MergeMediaAsset points every reference of the duplicate at the canonical asset, then deletes the duplicate
+func MergeMediaAsset(store storage.Storage, canonical *structs.MediaAssets, duplicate *structs.MediaAssets) error {
+	err := initializers.DB.Transaction(func(tx *gorm.DB) error {
+		return mergeMediaAssetRows(tx, canonical, duplicate)
+	})
+	if err != nil {
+		return err
+	}
+
+	DeleteMediaObjects(store, []string{duplicate.ObjectKey})
+	return nil
+}
+
+func mergeMediaAssetRows(tx *gorm.DB, canonical *structs.MediaAssets, duplicate *structs.MediaAssets) error {
+	if err := tx.Model(&structs.ProjectImages{}).Where("image_key = ?", duplicate.ObjectKey).Update("image_key", canonical.ObjectKey).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&structs.Technologies{}).Where("technology_image_key = ?", duplicate.ObjectKey).Update("technology_image_key", canonical.ObjectKey).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Delete(duplicate).Error
+}
+
+// FindDuplicateMedia groups media assets sharing the same content hash, the oldest asset of each group is canonical
+func FindDuplicateMedia() ([]structs.MediaDuplicateGroupModel, error) {
+	var hashes []string
+	err := initializers.DB.Model(&structs.MediaAssets{}).
+		Where("content_hash <> ''").
+		Group("content_hash").
+		Having("COUNT(*) > 1").
+		Pluck("content_hash", &hashes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]structs.MediaDuplicateGroupModel, 0, len(hashes))
+	for _, hash := range hashes {
+		var assets []structs.MediaAssets
+		if err := initializers.DB.Where("content_hash = ?", hash).Order("uploaded_at ASC, id ASC").Find(&assets).Error; err != nil {
+			return nil, err
+		}
+
+		if len(assets) < 2 {
+			continue
+		}
+
+		groups = append(groups, structs.MediaDuplicateGroupModel{
+			ContentHash: hash,
+			Canonical:   assets[0],
+			Duplicates:  assets[1:],
+		})
+	}
+
+	return groups, nil
+}
+
+// MergeDuplicateMedia merges every duplicate group into its canonical asset, returning how many duplicates were removed
+func MergeDuplicateMedia(store storage.Storage) (int, error) {
+	groups, err := FindDuplicateMedia()
+	if err != nil {
+		return 0, err
+	}
+
+	merged := 0
+	for _, group := range groups {
+		for i := range group.Duplicates {
+			if err := MergeMediaAsset(store, &group.Canonical, &group.Duplicates[i]); err != nil {
+				return merged, err
+			}
+			merged++
+		}
+	}
+
+	return merged, nil
+}
+
+// BackfillMediaHashes computes the content hash of assets registered before hashes were stored
+func BackfillMediaHashes(store storage.Storage) (int, error) {
+	var assets []structs.MediaAssets
+	if err := initializers.DB.Where("content_hash = '' OR content_hash IS NULL").Find(&assets).Error; err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, asset := range assets {
+		contentHash, _, _, err := inspectMediaObject(store, asset.ObjectKey)
+		if err != nil {
+			log.Warn("Error hashing media object ", asset.ObjectKey, ": ", err)
+			continue
+		}
+
+		if err := initializers.DB.Model(&asset).Update("content_hash", contentHash).Error; err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+func findCanonicalMediaAsset(db *gorm.DB, contentHash string) (*structs.MediaAssets, error) {
+	var asset structs.MediaAssets
+	err := db.Where("content_hash = ?", contentHash).Order("uploaded_at ASC, id ASC").First(&asset).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &asset, nil
+}
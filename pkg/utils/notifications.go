@@ -0,0 +1,302 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/notify"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	CONTACT_CREATED_EVENT = "contact.created"
+)
+
+const (
+	notificationRetryBaseDelay = 30 * time.Second
+	notificationRetryMaxDelay  = time.Hour
+	// deliveries stuck in SENDING for longer than this (e.g. the worker crashed) are retried
+	notificationSendingTimeout = 10 * time.Minute
+	notificationWorkerBatch    = 10
+)
+
+type ContactNotificationData struct {
+	ID        uint              `json:"id"`
+	Name      string            `json:"name"`
+	Email     string            `json:"email"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// defaultNotificationTemplates are used by channels without their own template
+var defaultNotificationTemplates = map[structs.NotificationChannelType]string{
+	structs.DISCORD_CHANNEL: "**From:** {{.Name}} ({{.Email}})\n\n{{.Message}}",
+	structs.SLACK_CHANNEL:   "*From:* {{.Name}} ({{.Email}})\n\n{{.Message}}",
+	structs.NTFY_CHANNEL:    "From: {{.Name}} ({{.Email}})\n\n{{.Message}}",
+	structs.WEBHOOK_CHANNEL: "New contact message from {{.Name}} ({{.Email}})",
+}
+
+var sampleContactNotification = ContactNotificationData{
+	ID:        1,
+	Name:      "Jane Doe",
+	Email:     "jane@example.com",
+	Message:   "Hi Jack,\nI'd love to chat about a project.",
+	Fields:    map[string]string{"company": "Acme Ltd"},
+	CreatedAt: time.Now(),
+}
+
+var notificationWorkerWake = make(chan struct{}, 1)
+
+// NotifyContactMessage queues a notification about the message on every enabled channel
+func NotifyContactMessage(message structs.ContactMessages) error {
+	return QueueNotification(CONTACT_CREATED_EVENT, "New contact message from "+message.Name, ContactNotificationData{
+		ID:        message.ID,
+		Name:      message.Name,
+		Email:     message.Email,
+		Message:   message.Message,
+		Fields:    message.Fields,
+		CreatedAt: message.CreatedAt,
+	})
+}
+
+// QueueNotification renders the event with each enabled channel's template and stores a delivery
+// per channel, the background worker sends them
+func QueueNotification(event string, title string, data interface{}) error {
+	var channels []structs.NotificationChannels
+	if err := initializers.DB.Where("is_enabled = ?", true).Find(&channels).Error; err != nil {
+		return err
+	}
+
+	if len(channels) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		message, err := RenderNotification(channel, data)
+		if err != nil {
+			log.Errorf("Error rendering notification for channel %d: %v", channel.ID, err)
+			continue
+		}
+
+		delivery := structs.NotificationDeliveries{
+			ChannelId:     channel.ID,
+			Event:         event,
+			Title:         title,
+			Message:       message,
+			Data:          string(payload),
+			Status:        structs.DELIVERY_PENDING,
+			MaxAttempts:   getEnvInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+			NextAttemptAt: time.Now(),
+		}
+
+		if err := initializers.DB.Create(&delivery).Error; err != nil {
+			return err
+		}
+	}
+
+	wakeNotificationWorker()
+	return nil
+}
+
+func RenderNotification(channel structs.NotificationChannels, data interface{}) (string, error) {
+	source := channel.Template
+	if source == "" {
+		source = defaultNotificationTemplates[channel.Type]
+	}
+
+	tmpl, err := template.New("notification").Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse notification template: %v", err)
+	}
+
+	var message bytes.Buffer
+	if err := tmpl.Execute(&message, data); err != nil {
+		return "", fmt.Errorf("failed to render notification template: %v", err)
+	}
+
+	return strings.TrimSpace(message.String()), nil
+}
+
+// ValidateNotificationChannel checks the channel can be built and its template renders
+func ValidateNotificationChannel(channel structs.NotificationChannels) error {
+	if _, err := NewChannelNotifier(channel); err != nil {
+		return err
+	}
+
+	_, err := RenderNotification(channel, sampleContactNotification)
+	return err
+}
+
+func NewChannelNotifier(channel structs.NotificationChannels) (notify.Notifier, error) {
+	switch channel.Type {
+	case structs.DISCORD_CHANNEL:
+		return notify.NewDiscordNotifier(channel.URL)
+	case structs.SLACK_CHANNEL:
+		return notify.NewSlackNotifier(channel.URL)
+	case structs.NTFY_CHANNEL:
+		return notify.NewNtfyNotifier(channel.URL, channel.Topic, channel.Secret)
+	case structs.WEBHOOK_CHANNEL:
+		return notify.NewWebhookNotifier(channel.URL, channel.Secret)
+	default:
+		return nil, fmt.Errorf("unknown notification channel type %q", channel.Type)
+	}
+}
+
+// SendTestNotification sends a sample contact notification straight away, without retries
+func SendTestNotification(channel structs.NotificationChannels) error {
+	notifier, err := NewChannelNotifier(channel)
+	if err != nil {
+		return err
+	}
+
+	message, err := RenderNotification(channel, sampleContactNotification)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(sampleContactNotification)
+	if err != nil {
+		return err
+	}
+
+	return notifier.Send(notify.Notification{
+		Event:   CONTACT_CREATED_EVENT,
+		Title:   "Test notification from " + sampleContactNotification.Name,
+		Message: message,
+		Data:    payload,
+	})
+}
+
+// RequeueNotification resets a (dead) delivery so the worker sends it again
+func RequeueNotification(delivery *structs.NotificationDeliveries) error {
+	err := initializers.DB.Model(delivery).Updates(map[string]interface{}{
+		"status":          structs.DELIVERY_PENDING,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	wakeNotificationWorker()
+	return nil
+}
+
+// StartNotificationWorker sends queued notifications in the background, retrying failures with
+// exponential backoff until NOTIFICATION_MAX_ATTEMPTS is reached and the delivery is dead-lettered
+func StartNotificationWorker() {
+	interval := time.Duration(getEnvInt("NOTIFICATION_WORKER_INTERVAL", 10)) * time.Second
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			processNotificationOutbox()
+
+			select {
+			case <-ticker.C:
+			case <-notificationWorkerWake:
+			}
+		}
+	}()
+
+	log.Info("Notification worker started")
+}
+
+func processNotificationOutbox() {
+	// Recover deliveries left in SENDING by a worker that stopped mid-send
+	initializers.DB.Model(&structs.NotificationDeliveries{}).
+		Where("status = ? AND updated_at < ?", structs.DELIVERY_SENDING, time.Now().Add(-notificationSendingTimeout)).
+		Update("status", structs.DELIVERY_PENDING)
+
+	for {
+		var deliveries []structs.NotificationDeliveries
+		err := initializers.DB.Preload("Channel").
+			Where("status = ? AND next_attempt_at <= ?", structs.DELIVERY_PENDING, time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(notificationWorkerBatch).
+			Find(&deliveries).Error
+		if err != nil {
+			log.Error("Error reading notification outbox: ", err)
+			return
+		}
+
+		for i := range deliveries {
+			sendNotificationDelivery(&deliveries[i])
+		}
+
+		if len(deliveries) < notificationWorkerBatch {
+			return
+		}
+	}
+}
+
+func sendNotificationDelivery(delivery *structs.NotificationDeliveries) {
+	// Claim the delivery, so other replicas running the worker skip it
+	result := initializers.DB.Model(&structs.NotificationDeliveries{}).
+		Where("id = ? AND status = ?", delivery.ID, structs.DELIVERY_PENDING).
+		Update("status", structs.DELIVERY_SENDING)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return
+	}
+
+	var sendErr error
+	if delivery.Channel.ID == 0 {
+		sendErr = fmt.Errorf("notification channel %d no longer exists", delivery.ChannelId)
+	} else if notifier, err := NewChannelNotifier(delivery.Channel); err != nil {
+		sendErr = err
+	} else {
+		sendErr = notifier.Send(notify.Notification{
+			Event:   delivery.Event,
+			Title:   delivery.Title,
+			Message: delivery.Message,
+			Data:    json.RawMessage(delivery.Data),
+		})
+	}
+
+	delivery.Attempts++
+	updates := map[string]interface{}{"attempts": delivery.Attempts}
+
+	if sendErr == nil {
+		now := time.Now()
+		updates["status"] = structs.DELIVERY_SENT
+		updates["sent_at"] = &now
+		updates["last_error"] = ""
+	} else {
+		updates["last_error"] = sendErr.Error()
+
+		// A channel deleted after the delivery was queued won't come back, so don't retry
+		if delivery.Attempts >= delivery.MaxAttempts || delivery.Channel.ID == 0 {
+			updates["status"] = structs.DELIVERY_DEAD
+			log.Errorf("Notification %d to channel %d dead-lettered after %d attempts: %v", delivery.ID, delivery.ChannelId, delivery.Attempts, sendErr)
+		} else {
+			updates["status"] = structs.DELIVERY_PENDING
+			updates["next_attempt_at"] = time.Now().Add(retryDelay(delivery.Attempts, notificationRetryBaseDelay, notificationRetryMaxDelay))
+			log.Warnf("Notification %d to channel %d failed (attempt %d), retrying: %v", delivery.ID, delivery.ChannelId, delivery.Attempts, sendErr)
+		}
+	}
+
+	if err := initializers.DB.Model(delivery).Updates(updates).Error; err != nil {
+		log.Error("Error updating notification delivery: ", err)
+	}
+}
+
+func wakeNotificationWorker() {
+	select {
+	case notificationWorkerWake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+)
+
+// NormaliseRepositoryActivity flattens the repository's commits, releases and merged pull requests into
+// activity events, newest first
+func NormaliseRepositoryActivity(repository string, activity *github.RepositoryActivity) []structs.ActivityEventModel {
+	events := make([]structs.ActivityEventModel, 0, len(activity.Commits)+len(activity.Releases)+len(activity.PullRequests))
+
+	for _, commit := range activity.Commits {
+		event := structs.ActivityEventModel{
+			ID:         string(structs.COMMIT_ACTIVITY) + ":" + commit.Oid,
+			Type:       structs.COMMIT_ACTIVITY,
+			Title:      commit.MessageHeadline,
+			Ref:        commit.AbbreviatedOid,
+			URL:        commit.URL,
+			Actor:      commit.Author.Name,
+			Repository: repository,
+			OccurredAt: commit.CommittedDate,
+		}
+		// Commits by emails not linked to a GitHub account only have the git author name
+		if commit.Author.User != nil {
+			event.Actor = commit.Author.User.Login
+			event.ActorAvatarURL = commit.Author.User.AvatarURL
+		}
+		events = append(events, event)
+	}
+
+	for _, release := range activity.Releases {
+		title := release.Name
+		if title == "" {
+			title = release.TagName
+		}
+
+		event := structs.ActivityEventModel{
+			ID:         string(structs.RELEASE_ACTIVITY) + ":" + repository + "@" + release.TagName,
+			Type:       structs.RELEASE_ACTIVITY,
+			Title:      title,
+			Ref:        release.TagName,
+			URL:        release.URL,
+			Repository: repository,
+			OccurredAt: release.PublishedAt,
+		}
+		setActivityActor(&event, release.Author)
+		events = append(events, event)
+	}
+
+	for _, pullRequest := range activity.PullRequests {
+		event := structs.ActivityEventModel{
+			ID:         string(structs.PULL_REQUEST_ACTIVITY) + ":" + repository + "#" + strconv.Itoa(pullRequest.Number),
+			Type:       structs.PULL_REQUEST_ACTIVITY,
+			Title:      pullRequest.Title,
+			Ref:        "#" + strconv.Itoa(pullRequest.Number),
+			URL:        pullRequest.URL,
+			Repository: repository,
+			OccurredAt: pullRequest.MergedAt,
+		}
+		setActivityActor(&event, pullRequest.Author)
+		events = append(events, event)
+	}
+
+	SortActivity(events)
+	return events
+}
+
+// SortActivity orders events newest first
+func SortActivity(events []structs.ActivityEventModel) {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].OccurredAt.After(events[j].OccurredAt) })
+}
+
+// setActivityActor fills in the author, which is nil for deleted ("ghost") accounts
+func setActivityActor(event *structs.ActivityEventModel, actor *github.Actor) {
+	if actor != nil {
+		event.Actor = actor.Login
+		event.ActorAvatarURL = actor.AvatarURL
+	}
+}
@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/github"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrNoRepository = errors.New("project has no GitHub repository")
+
+const (
+	repositorySyncTimeout       = 30 * time.Second
+	repositorySyncCheckInterval = time.Minute
+)
+
+var repositorySyncQueue = make(chan uint, 64)
+
+// StartRepositorySyncWorker re-syncs each project's GitHub repository once it is older than
+// GITHUB_SYNC_INTERVAL minutes, and syncs queued projects straight away
+func StartRepositorySyncWorker() {
+	interval := time.Duration(getEnvInt("GITHUB_SYNC_INTERVAL", 360)) * time.Minute
+
+	go func() {
+		ticker := time.NewTicker(repositorySyncCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			syncStaleRepositories(interval)
+
+			select {
+			case <-ticker.C:
+			case projectID := <-repositorySyncQueue:
+				if _, err := SyncProjectRepository(projectID); err != nil && !errors.Is(err, ErrNoRepository) {
+					log.Warn("Error syncing repository for project ", projectID, ": ", err)
+				}
+			}
+		}
+	}()
+
+	log.Info("Repository sync worker started")
+}
+
+// QueueRepositorySync asks the worker to sync the project, e.g. after its GitHub URL changed
+func QueueRepositorySync(projectID uint) {
+	select {
+	case repositorySyncQueue <- projectID:
+	default:
+		// The periodic sync will pick it up
+	}
+}
+
+func syncStaleRepositories(interval time.Duration) {
+	var projectIDs []uint
+	err := initializers.DB.Model(&structs.ProjectURLs{}).
+		Joins("JOIN projects ON projects.id = project_urls.project_id AND projects.deleted_at IS NULL").
+		Joins("LEFT JOIN project_repositories ON project_repositories.project_id = project_urls.project_id AND project_repositories.deleted_at IS NULL").
+		Where("project_urls.git_hub_url <> '' AND (project_repositories.id IS NULL OR project_repositories.synced_at < ?)", time.Now().Add(-interval)).
+		Pluck("project_urls.project_id", &projectIDs).Error
+	if err != nil {
+		log.Error("Error finding repositories to sync: ", err)
+		return
+	}
+
+	for _, projectID := range projectIDs {
+		if _, err := SyncProjectRepository(projectID); err != nil && !errors.Is(err, ErrNoRepository) {
+			log.Warn("Error syncing repository for project ", projectID, ": ", err)
+		}
+	}
+}
+
+// SyncProjectRepository fetches the repository linked by the project's GitHub URL and stores it as the
+// project's snapshot. Failed syncs keep the previous snapshot and record the error.
+func SyncProjectRepository(projectID uint) (*structs.ProjectRepositories, error) {
+	var urls structs.ProjectURLs
+	err := initializers.DB.Where("project_id = ?", projectID).First(&urls).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if urls.GitHubURL == "" {
+		// The link was removed, so is the snapshot
+		if err := initializers.DB.Unscoped().Where("project_id = ?", projectID).Delete(&structs.ProjectRepositories{}).Error; err != nil {
+			return nil, err
+		}
+		return nil, ErrNoRepository
+	}
+
+	var snapshot structs.ProjectRepositories
+	if err := initializers.DB.Where("project_id = ?", projectID).First(&snapshot).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	owner, name, parseErr := github.ParseRepositoryURL(urls.GitHubURL)
+
+	// Don't keep showing the old repository's data if the link now points elsewhere
+	if !strings.EqualFold(snapshot.Owner, owner) || !strings.EqualFold(snapshot.Name, name) {
+		snapshot = structs.ProjectRepositories{GormModel: snapshot.GormModel, Owner: owner, Name: name}
+	}
+	snapshot.ProjectId = projectID
+	snapshot.SyncedAt = time.Now()
+
+	if parseErr != nil {
+		return nil, saveSyncError(&snapshot, parseErr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), repositorySyncTimeout)
+	defer cancel()
+
+	details, err := initializers.GitHub.GetRepository(ctx, owner, name)
+	if err != nil {
+		return nil, saveSyncError(&snapshot, err)
+	}
+
+	applyRepositoryDetails(&snapshot, details)
+	snapshot.SyncError = ""
+
+	if err := saveSnapshot(&snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// saveSnapshot upserts on project_id, so the worker and a manual resync of the same project can't both insert
+func saveSnapshot(snapshot *structs.ProjectRepositories) error {
+	err := initializers.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}},
+		UpdateAll: true,
+	}).Create(snapshot).Error
+	if err != nil {
+		return err
+	}
+
+	// The insert may have updated the existing row instead, so read back its ID & timestamps
+	return initializers.DB.Where("project_id = ?", snapshot.ProjectId).First(snapshot).Error
+}
+
+func applyRepositoryDetails(snapshot *structs.ProjectRepositories, details *github.RepositoryDetails) {
+	if owner, name, found := strings.Cut(details.NameWithOwner, "/"); found {
+		snapshot.Owner = owner
+		snapshot.Name = name
+	}
+	snapshot.URL = details.URL
+	snapshot.Description = details.Description
+	snapshot.Stars = details.StargazerCount
+	snapshot.Forks = details.ForkCount
+	snapshot.OpenIssues = details.OpenIssues
+	snapshot.LastCommitAt = details.LastCommitAt
+	snapshot.IsArchived = details.IsArchived
+	snapshot.IsPrivate = details.IsPrivate
+	snapshot.Topics = details.Topics
+
+	snapshot.Language = ""
+	if details.PrimaryLanguage != nil {
+		snapshot.Language = details.PrimaryLanguage.Name
+	}
+
+	snapshot.LatestReleaseTag, snapshot.LatestReleaseName, snapshot.LatestReleaseURL = "", "", ""
+	snapshot.LatestReleasePublishedAt = nil
+	if release := details.LatestRelease; release != nil {
+		snapshot.LatestReleaseTag = release.TagName
+		snapshot.LatestReleaseName = release.Name
+		snapshot.LatestReleaseURL = release.URL
+		publishedAt := release.PublishedAt
+		snapshot.LatestReleasePublishedAt = &publishedAt
+	}
+
+	snapshot.License, snapshot.LicenseName = "", ""
+	if details.License != nil {
+		snapshot.License = details.License.SpdxID
+		snapshot.LicenseName = details.License.Name
+	}
+}
+
+func saveSyncError(snapshot *structs.ProjectRepositories, syncErr error) error {
+	snapshot.SyncError = syncErr.Error()
+	if err := saveSnapshot(snapshot); err != nil {
+		log.Error("Error saving repository sync error: ", err)
+	}
+	return syncErr
+}
+
+// technologyAliases maps common GitHub topic spellings onto normalised technology names
+var technologyAliases = map[string]string{
+	"golang":     "go",
+	"js":         "javascript",
+	"ts":         "typescript",
+	"reactjs":    "react",
+	"vuejs":      "vue",
+	"cpp":        "c++",
+	"cplusplus":  "c++",
+	"csharp":     "c#",
+	"postgres":   "postgresql",
+	"k8s":        "kubernetes",
+	"tailwind":   "tailwindcss",
+	"mongo":      "mongodb",
+	"dotnet":     ".net",
+	"dotnetcore": ".net",
+}
+
+// normaliseTechnologyName lowercases the name and drops separators, so "Next.js" matches the "nextjs" topic
+func normaliseTechnologyName(name string) string {
+	normalised := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+
+	if alias, ok := technologyAliases[normalised]; ok {
+		return normaliseTechnologyName(alias)
+	}
+	return normalised
+}
+
+// SuggestProjectTechnologies matches the synced repository's topics and primary language against
+// Technologies, leaving out technologies already assigned to the project
+func SuggestProjectTechnologies(projectID uint) ([]structs.Technologies, error) {
+	var snapshot structs.ProjectRepositories
+	if err := initializers.DB.Where("project_id = ?", projectID).First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoRepository
+		}
+		return nil, err
+	}
+
+	var technologies []structs.Technologies
+	err := initializers.DB.
+		Where("id NOT IN (?)", initializers.DB.Model(&structs.ProjectTechnologies{}).Select("technology_id").Where("project_id = ?", projectID)).
+		Find(&technologies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]structs.Technologies, len(technologies))
+	for _, technology := range technologies {
+		byName[normaliseTechnologyName(technology.TechnologyName)] = technology
+	}
+
+	suggestions := []structs.Technologies{}
+	seen := make(map[uint]bool)
+	for _, topic := range append([]string{snapshot.Language}, snapshot.Topics...) {
+		if technology, ok := byName[normaliseTechnologyName(topic)]; ok && topic != "" && !seen[technology.ID] {
+			seen[technology.ID] = true
+			suggestions = append(suggestions, technology)
+		}
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	AUTO_REPLY_SETTING   = "contact.autoReply"
+	CONTACT_FORM_SETTING = "contact.formSchema"
+	GITHUB_USERS_SETTING = "github.allowedUsers"
+)
+
+// AutoReplySettings controls the receipt email sent to visitors. Subject & Body are text templates
+// over the submission ({{.Name}}, {{.Email}}, {{.Message}}, {{range .Fields}}) and use the defaults when empty.
+type AutoReplySettings struct {
+	Enabled bool   `json:"enabled"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+var defaultAutoReplySettings = AutoReplySettings{Enabled: true}
+
+// GetSetting decodes the stored setting into value, leaving value untouched if it was never saved
+func GetSetting(key string, value interface{}) error {
+	var setting structs.Settings
+	err := initializers.DB.Where("`key` = ?", key).First(&setting).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(setting.Value), value)
+}
+
+func SaveSetting(key string, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return initializers.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&structs.Settings{Key: key, Value: string(encoded)}).Error
+}
+
+func GetAutoReplySettings() (AutoReplySettings, error) {
+	settings := defaultAutoReplySettings
+	err := GetSetting(AUTO_REPLY_SETTING, &settings)
+	return settings, err
+}
+
+// GitHubUserSettings limits which logins the GitHub endpoints look up with our access token
+type GitHubUserSettings struct {
+	AllowedUsers []string `json:"allowedUsers"`
+	// DefaultUser is used when no user is requested
+	DefaultUser string `json:"defaultUser"`
+}
+
+var githubLoginPattern = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9]|-[a-zA-Z0-9]){0,38}$`)
+
+// GetGitHubUserSettings falls back to GITHUB_ALLOWED_USERS & GITHUB_DEFAULT_USER until the list is edited
+func GetGitHubUserSettings() (GitHubUserSettings, error) {
+	settings := GitHubUserSettings{
+		AllowedUsers: []string{},
+		DefaultUser:  os.Getenv("GITHUB_DEFAULT_USER"),
+	}
+
+	for _, login := range strings.Split(os.Getenv("GITHUB_ALLOWED_USERS"), ",") {
+		if login = strings.TrimSpace(login); login != "" {
+			settings.AllowedUsers = append(settings.AllowedUsers, login)
+		}
+	}
+
+	// The default user is always allowed
+	if settings.DefaultUser != "" && !settings.IsAllowed(settings.DefaultUser) {
+		settings.AllowedUsers = append(settings.AllowedUsers, settings.DefaultUser)
+	}
+
+	err := GetSetting(GITHUB_USERS_SETTING, &settings)
+	return settings, err
+}
+
+// Validate checks every login is a valid GitHub username and the default user is allowed
+func (settings GitHubUserSettings) Validate() error {
+	for _, login := range settings.AllowedUsers {
+		if !githubLoginPattern.MatchString(login) {
+			return fmt.Errorf("%q is not a valid GitHub login", login)
+		}
+	}
+
+	if settings.DefaultUser != "" && !settings.IsAllowed(settings.DefaultUser) {
+		return fmt.Errorf("default user %q is not in the allowed users", settings.DefaultUser)
+	}
+
+	return nil
+}
+
+// IsAllowed compares logins case-insensitively, like GitHub does
+func (settings GitHubUserSettings) IsAllowed(login string) bool {
+	for _, allowed := range settings.AllowedUsers {
+		if strings.EqualFold(allowed, login) {
+			return true
+		}
+	}
+	return false
+}
+
+func GetContactFormSchema() (ContactFormSchema, error) {
+	schema := ContactFormSchema{Fields: []ContactFormField{}}
+	err := GetSetting(CONTACT_FORM_SETTING, &schema)
+	return schema, err
+}
+
+// Apply renders the custom subject & body into the receipt email data
+func (settings AutoReplySettings) Apply(data *ContactEmailData) error {
+	var err error
+	if data.Subject, err = renderAutoReplyTemplate("subject", settings.Subject, data); err != nil {
+		return err
+	}
+
+	data.Body, err = renderAutoReplyTemplate("body", settings.Body, data)
+	return err
+}
+
+// Validate checks the subject & body templates render, before they are saved
+func (settings AutoReplySettings) Validate() error {
+	sample := emailTemplateSamples[CONTACT_RECEIPT_EMAIL].(ContactEmailData)
+	return settings.Apply(&sample)
+}
+
+func renderAutoReplyTemplate(name string, source string, data *ContactEmailData) (string, error) {
+	if source == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New(name).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid auto-reply %s: %v", name, err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("invalid auto-reply %s: %v", name, err)
+	}
+
+	return strings.TrimSpace(rendered.String()), nil
+}
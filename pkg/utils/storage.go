@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/storage"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+)
+
+// GeneratePresignedPost returns the presigned upload URL and the object key it uploads to
+func GeneratePresignedPost(store storage.Storage, category structs.UploadCategory) (string, string, error) {
+	key := generateUploadKey(category)
+
+	urlStr, err := store.PresignPut(key, 15*time.Minute)
+	if err != nil {
+		return "", "", err
+	}
+
+	return urlStr, key, nil
+}
+
+func generateUploadKey(category structs.UploadCategory) string {
+	return fmt.Sprintf("%s/%s/%d", os.Getenv("BUCKET_ENDPOINT_URI"), category, time.Now().UnixNano()/1e3)
+}
+
+// ResolveStorageKey accepts either an object key or a URL pointing at the configured
+// storage (public, CDN or presigned) and returns the object key once it is confirmed to exist.
+// It has no side effects, register the key with RegisterMediaReferences once the entity is saved.
+func ResolveStorageKey(store storage.Storage, reference string) (string, error) {
+	key := reference
+
+	if parsedURL, err := url.Parse(reference); err == nil && parsedURL.IsAbs() {
+		key, err = store.KeyFromURL(reference)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := store.Head(key); err != nil {
+		return "", fmt.Errorf("failed to get object: %v", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/initializers"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/storage"
+	"github.com/Jake4-CX/portfolio-website-v2-backend/pkg/structs"
+)
+
+type UploadPolicy struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+var imageContentTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+var UploadPolicies = map[structs.UploadCategory]UploadPolicy{
+	structs.PROJECT_IMAGE:    {MaxSize: 10 << 20, AllowedTypes: imageContentTypes},
+	structs.TECHNOLOGY_IMAGE: {MaxSize: 2 << 20, AllowedTypes: imageContentTypes},
+}
+
+var (
+	ErrUnknownUploadCategory = errors.New("unknown upload category")
+	ErrUploadTooLarge        = errors.New("upload exceeds the maximum size for this category")
+	ErrUploadTypeNotAllowed  = errors.New("upload content type is not allowed for this category")
+)
+
+// how much of the upload is kept in memory to read the image dimensions from
+const uploadHeaderSize = 64 << 10
+
+// StreamUpload streams the body straight to storage while enforcing the category's size and
+// type limits. The content type is detected from the magic bytes, not trusted from the client.
+// Duplicate content resolves to the existing asset.
+func StreamUpload(store storage.Storage, category structs.UploadCategory, body io.Reader) (*structs.MediaAssets, bool, error) {
+	policy, exists := UploadPolicies[category]
+	if !exists {
+		return nil, false, ErrUnknownUploadCategory
+	}
+
+	bufferedBody := bufio.NewReaderSize(body, 512)
+	sniffed, err := bufferedBody.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, false, fmt.Errorf("failed to read upload: %v", err)
+	}
+
+	contentType := http.DetectContentType(sniffed)
+	if !isAllowedContentType(contentType, policy.AllowedTypes) {
+		return nil, false, fmt.Errorf("%w: %s", ErrUploadTypeNotAllowed, contentType)
+	}
+
+	hash := sha256.New()
+	header := &cappedBuffer{limit: uploadHeaderSize}
+	limitedBody := &sizeLimitedReader{reader: bufferedBody, remaining: policy.MaxSize}
+
+	key := generateUploadKey(category)
+	if err := store.Put(key, io.TeeReader(limitedBody, io.MultiWriter(hash, header)), contentType); err != nil {
+		if errors.Is(err, ErrUploadTooLarge) || limitedBody.exceeded {
+			return nil, false, ErrUploadTooLarge
+		}
+		return nil, false, err
+	}
+
+	var imageConfig *image.Config
+	config, format, err := image.DecodeConfig(bytes.NewReader(header.Bytes()))
+	if err == nil {
+		imageConfig = &config
+	}
+
+	asset, err := createMediaAsset(initializers.DB, store, key, hex.EncodeToString(hash.Sum(nil)), imageConfig, format)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return deduplicateMediaAsset(store, asset)
+}
+
+func isAllowedContentType(contentType string, allowedTypes []string) bool {
+	for _, allowedType := range allowedTypes {
+		if contentType == allowedType {
+			return true
+		}
+	}
+	return false
+}
+
+// sizeLimitedReader fails the read (and so the upload) once more than the allowed bytes are read
+type sizeLimitedReader struct {
+	reader    io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		r.exceeded = true
+		return 0, ErrUploadTooLarge
+	}
+
+	// Read one byte past the limit so an upload of exactly the limit is allowed
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		r.exceeded = true
+		return n, ErrUploadTooLarge
+	}
+
+	return n, err
+}
+
+// cappedBuffer keeps only the first limit bytes written to it
+type cappedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}